@@ -16,6 +16,10 @@
 
 package logger
 
+import (
+	"time"
+)
+
 // Backend is responsible for serializing and writing log entries.
 // It can also filter logs and process only some of them.
 type Backend struct {
@@ -27,23 +31,114 @@ type Backend struct {
 	Serializer
 	// Writer writes data to final destination.
 	Writer
+	// Enrichers add information to entries written by this backend only.
+	Enrichers []Enricher
+}
+
+// Flusher is implemented by Filters, Serializers and Writers holding entries which have not been
+// written yet (e.g. BackendDedup or BackendFingersCrossed).
+type Flusher interface {
+	// Flush writes all held entries.
+	Flush() error
+}
+
+// flush calls Flush method of every element of the backend implementing Flusher interface,
+// including Filters wrapped by the backend's Filter (see filterWrapper). Wrapped Filters are
+// flushed before their wrappers, so entries they write (e.g. summaries) are flushed too.
+// All of them are flushed even if some fail. The first error is returned.
+func (b *Backend) flush() (err error) {
+	var elems []interface{}
+	for f := b.Filter; f != nil; {
+		elems = append([]interface{}{f}, elems...)
+		w, ok := f.(filterWrapper)
+		if !ok {
+			break
+		}
+		f = w.wrappedFilter()
+	}
+	elems = append(elems, b.Serializer, b.Writer)
+	for _, elem := range elems {
+		f, ok := elem.(Flusher)
+		if !ok {
+			continue
+		}
+		if ferr := f.Flush(); ferr != nil && err == nil {
+			err = ferr
+		}
+	}
+	return err
 }
 
 // process method filters, serializes and writes log message.
 func (b *Backend) process(entry *Entry) error {
+	return b.processStats(entry, nil)
+}
+
+// processStats method filters, serializes and writes log message collecting statistics.
+// Statistics are not collected if stats is nil. Lazy values of entry are evaluated only if it
+// passes the filter.
+func (b *Backend) processStats(entry *Entry, stats *backendStats) error {
+	start := time.Now()
 	pass, err := b.Filter.Verify(entry)
 	if err != nil {
+		stats.failed(entry.Level)
 		return err
 	}
 	if !pass {
+		stats.filtered(entry.Level)
 		return nil
 	}
 
-	buf, err := b.Serializer.Serialize(entry)
+	entry = b.enrich(entry.resolved())
+	buf, pooled, err := b.serialize(entry)
 	if err != nil {
+		stats.failed(entry.Level)
 		return err
 	}
 
 	_, err = b.Writer.Write(entry.Level, buf)
-	return err
+	putBuffer(pooled)
+	if err != nil {
+		stats.failed(entry.Level)
+		return err
+	}
+	stats.accepted(entry.Level, len(buf), time.Since(start))
+	return nil
+}
+
+// enrich returns entry with backend's enrichers run on it. Entry is copied, so that other
+// backends do not see added information.
+func (b *Backend) enrich(entry *Entry) *Entry {
+	if len(b.Enrichers) == 0 {
+		return entry
+	}
+	c := entry.clone()
+	for _, e := range b.Enrichers {
+		e.Enrich(c)
+	}
+	return c.resolved()
+}
+
+// serialize serializes entry. If Serializer implements AppendSerializer, entry is serialized
+// into a pooled buffer, which is also returned and should be released with putBuffer
+// after use.
+func (b *Backend) serialize(entry *Entry) ([]byte, *[]byte, error) {
+	as, ok := b.Serializer.(AppendSerializer)
+	if !ok {
+		buf, err := b.Serializer.Serialize(entry)
+		return buf, nil, err
+	}
+	pooled := getBuffer()
+	buf, err := as.AppendSerialize(*pooled, entry)
+	if err != nil {
+		putBuffer(pooled)
+		return nil, nil, err
+	}
+	*pooled = buf
+	return buf, pooled, nil
+}
+
+// usesCallContext returns true if Filter or Serializer of the backend uses call context.
+func (b *Backend) usesCallContext() bool {
+	return usesCallContext(b.Filter) || usesCallContext(b.Serializer)
 }
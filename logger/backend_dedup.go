@@ -0,0 +1,214 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+const (
+	// RepeatedProperty defines key of property holding number of suppressed repetitions
+	// in summary entries created by BackendDedup.
+	RepeatedProperty = "repeated"
+)
+
+// BackendDedup collapses consecutive identical entries passed to a wrapped Backend.
+// Entries are identical if they have the same level, message and call context
+// (and properties if CompareProperties is set). Repetitions of an entry within Window
+// are suppressed and a single summary entry containing RepeatedProperty is written
+// instead, like syslogd's "last message repeated N times".
+//
+// Summary is written before the next different entry, when Window passes or on Flush
+// and Close calls. Failures of writing summaries before a different entry or when Window passes
+// are passed to ErrorHandler of the Logger. Close must be called to stop the timer when
+// BackendDedup is no longer used.
+//
+// BackendDedup implements Filter interface. Use Backend method to get a Backend that can be
+// registered in Logger.
+type BackendDedup struct {
+	// Window defines time in which repetitions are suppressed.
+	Window time.Duration
+	// CompareProperties set to true makes entries with different properties or fields
+	// not identical.
+	CompareProperties bool
+
+	// backend is the wrapped backend.
+	backend Backend
+	// last is a copy of the last entry written to the wrapped backend.
+	last *Entry
+	// repeated is the number of suppressed repetitions of the last entry.
+	repeated uint64
+	// start is the beginning of current suppression window.
+	start time.Time
+	// mutex protects BackendDedup structure from concurrent access.
+	mutex *sync.Mutex
+	// done stops the timer.
+	done chan struct{}
+	// closeOnce ensures that the timer is stopped only once.
+	closeOnce sync.Once
+	// now returns current time.
+	now func() time.Time
+}
+
+// NewBackendDedup creates and returns a new BackendDedup object wrapping given backend
+// and suppressing repetitions within window.
+func NewBackendDedup(b Backend, window time.Duration) *BackendDedup {
+	d := &BackendDedup{
+		Window:  window,
+		backend: b,
+		mutex:   new(sync.Mutex),
+		done:    make(chan struct{}),
+		now:     time.Now,
+	}
+	if window > 0 {
+		go d.loop(window)
+	}
+	return d
+}
+
+// Backend returns a Backend using BackendDedup as its Filter and wrapped backend's Serializer,
+// Writer and Enrichers.
+func (d *BackendDedup) Backend() Backend {
+	return Backend{
+		Filter:     d,
+		Serializer: d.backend.Serializer,
+		Writer:     d.backend.Writer,
+		Enrichers:  d.backend.Enrichers,
+	}
+}
+
+// wrappedFilter returns Filter of the wrapped backend.
+// It implements filterWrapper interface in BackendDedup.
+func (d *BackendDedup) wrappedFilter() Filter {
+	return d.backend.Filter
+}
+
+// loop writes summaries of expired windows until BackendDedup is closed.
+func (d *BackendDedup) loop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			d.expire()
+		case <-d.done:
+			return
+		}
+	}
+}
+
+// expire writes summary if current suppression window has passed.
+func (d *BackendDedup) expire() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.repeated == 0 || d.now().Sub(d.start) < d.Window {
+		return
+	}
+	last := d.last
+	if err := d.summarize(); err != nil {
+		last.Logger.handleFilterError(d, last, err)
+	}
+}
+
+// summarize writes summary of suppressed repetitions to the wrapped backend
+// and starts a new suppression window. It must be called with mutex locked.
+func (d *BackendDedup) summarize() error {
+	if d.repeated == 0 {
+		return nil
+	}
+	summary := &Entry{
+		Logger:      d.last.Logger,
+		Level:       d.last.Level,
+		Message:     fmt.Sprintf("Last message repeated %d times.", d.repeated),
+		Properties:  Properties{RepeatedProperty: d.repeated},
+		Timestamp:   d.now(),
+		CallContext: d.last.CallContext,
+	}
+	d.repeated = 0
+	d.start = summary.Timestamp
+
+	summary = d.backend.enrich(summary)
+	buf, err := d.backend.Serializer.Serialize(summary)
+	if err != nil {
+		return err
+	}
+	_, err = d.backend.Writer.Write(summary.Level, buf)
+	return err
+}
+
+// identical verifies if entry is a repetition of the last entry.
+func (d *BackendDedup) identical(entry *Entry) bool {
+	if d.last == nil || d.last.Level != entry.Level || d.last.Message != entry.Message {
+		return false
+	}
+	if (d.last.CallContext == nil) != (entry.CallContext == nil) {
+		return false
+	}
+	if d.last.CallContext != nil && *d.last.CallContext != *entry.CallContext {
+		return false
+	}
+	return !d.CompareProperties || (reflect.DeepEqual(d.last.Properties, entry.Properties) &&
+		reflect.DeepEqual(d.last.Fields, entry.Fields))
+}
+
+// Verify passes entry to the wrapped backend's Filter and suppresses repetitions
+// of the last entry. Pending summary is written before a different entry is accepted.
+// The entry is accepted even if writing the summary fails.
+// It implements Filter interface in BackendDedup type.
+func (d *BackendDedup) Verify(entry *Entry) (bool, error) {
+	if entry == nil {
+		return false, ErrInvalidEntry
+	}
+	pass, err := d.backend.Filter.Verify(entry)
+	if err != nil || !pass {
+		return pass, err
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.identical(entry) && d.now().Sub(d.start) < d.Window {
+		d.repeated++
+		return false, nil
+	}
+	last := d.last
+	if err = d.summarize(); err != nil {
+		// Failure of writing summary must not cause loss of the new entry.
+		last.Logger.handleFilterError(d, last, err)
+	}
+	d.last = entry.clone()
+	d.start = d.now()
+	return true, nil
+}
+
+// Flush writes pending summary immediately.
+func (d *BackendDedup) Flush() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.summarize()
+}
+
+// Close stops the timer and writes pending summary.
+func (d *BackendDedup) Close() (err error) {
+	d.closeOnce.Do(func() {
+		close(d.done)
+		err = d.Flush()
+	})
+	return err
+}
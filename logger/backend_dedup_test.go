@@ -0,0 +1,221 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"errors"
+	"time"
+
+	gomock "github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BackendDedup", func() {
+	var (
+		w   *writerCollector
+		d   *BackendDedup
+		b   Backend
+		now time.Time
+		L   *Logger
+	)
+
+	BeforeEach(func() {
+		w = new(writerCollector)
+		d = NewBackendDedup(Backend{
+			Filter:     NewFilterPassAll(),
+			Serializer: newPlainSerializerText(),
+			Writer:     w,
+		}, time.Minute)
+		now = time.Unix(1234567890, 0)
+		d.now = func() time.Time { return now }
+		b = d.Backend()
+		L = NewLogger()
+		L.AddBackend("dedup", b)
+	})
+	AfterEach(func() {
+		Expect(d.Close()).To(Succeed())
+	})
+
+	logRepeated := func(n int, msg string) {
+		for i := 0; i < n; i++ {
+			L.WithProperty("i", i).Warning(msg)
+		}
+	}
+
+	Describe("NewBackendDedup", func() {
+		It("should create a new object wrapping backend", func() {
+			Expect(d).NotTo(BeNil())
+			Expect(d.Window).To(Equal(time.Minute))
+			Expect(b.Filter).To(Equal(d))
+			Expect(b.Writer).To(Equal(w))
+		})
+	})
+	Describe("Verify", func() {
+		It("should run wrapped backend's enrichers on summaries", func() {
+			Expect(d.Close()).To(Succeed())
+			d = NewBackendDedup(Backend{
+				Filter:     NewFilterPassAll(),
+				Serializer: newPlainSerializerText(),
+				Writer:     w,
+				Enrichers:  []Enricher{EnricherFields{String("env", "test")}},
+			}, time.Minute)
+			L.AddBackend("dedup", d.Backend())
+			logRepeated(2, "failed")
+			L.Info("done")
+
+			Expect(w.Messages()).To(Equal([]string{
+				`[WAR] failed {env:test;i:0;}`,
+				`[WAR] "Last message repeated 1 times." {env:test;repeated:1;}`,
+				`[INF] done {env:test;}`,
+			}))
+		})
+		It("should collapse repeated entries and write summary before a different one", func() {
+			logRepeated(4, "flashing failed")
+			L.Info("done")
+
+			Expect(w.Messages()).To(Equal([]string{
+				`[WAR] "flashing failed" {i:0;}`,
+				`[WAR] "Last message repeated 3 times." {repeated:3;}`,
+				`[INF] done `,
+			}))
+		})
+		It("should not collapse entries from different call sites", func() {
+			L.Warning("flashing failed")
+			L.Warning("flashing failed")
+			Expect(w.Messages()).To(HaveLen(2))
+		})
+		It("should not collapse entries with different properties if requested", func() {
+			d.CompareProperties = true
+			logRepeated(3, "flashing failed")
+			Expect(w.Messages()).To(HaveLen(3))
+		})
+		It("should not collapse entries after window passes", func() {
+			logRepeated(2, "flashing failed")
+			now = now.Add(time.Minute)
+			logRepeated(1, "flashing failed")
+
+			Expect(w.Messages()).To(Equal([]string{
+				`[WAR] "flashing failed" {i:0;}`,
+				`[WAR] "Last message repeated 1 times." {repeated:1;}`,
+				`[WAR] "flashing failed" {i:0;}`,
+			}))
+		})
+		It("should respect wrapped backend's filter", func() {
+			ctrl := gomock.NewController(GinkgoT())
+			defer ctrl.Finish()
+			mf := NewMockFilter(ctrl)
+			testError := errors.New("Test Error")
+			d.backend.Filter = mf
+
+			mf.EXPECT().Verify(gomock.Any()).Return(false, nil)
+			pass, err := d.Verify(&Entry{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pass).To(BeFalse())
+
+			mf.EXPECT().Verify(gomock.Any()).Return(false, testError)
+			pass, err = d.Verify(&Entry{})
+			Expect(err).To(Equal(testError))
+			Expect(pass).To(BeFalse())
+		})
+		It("should accept different entry if writing summary fails", func() {
+			ctrl := gomock.NewController(GinkgoT())
+			defer ctrl.Finish()
+			ms := NewMockSerializer(ctrl)
+			h := NewErrorHandlerCount(nil)
+			L.SetErrorHandler(h)
+			logRepeated(2, "flashing failed")
+
+			d.backend.Serializer = ms
+			ms.EXPECT().Serialize(gomock.Any()).Return(nil, errors.New("Test Error"))
+			L.Warning("flashing succeeded")
+			Expect(h.Errors()).To(Equal(map[string]uint64{"dedup": 1}))
+			Expect(w.Messages()).To(Equal([]string{
+				`[WAR] "flashing failed" {i:0;}`,
+				`[WAR] "flashing succeeded" `,
+			}))
+			Expect(d.repeated).To(BeZero())
+		})
+		It("should return error for nil entry", func() {
+			pass, err := d.Verify(nil)
+			Expect(err).To(Equal(ErrInvalidEntry))
+			Expect(pass).To(BeFalse())
+		})
+	})
+	Describe("Flush", func() {
+		It("should write pending summary", func() {
+			logRepeated(3, "flashing failed")
+			Expect(d.Flush()).To(Succeed())
+			Expect(d.Flush()).To(Succeed())
+
+			Expect(w.Messages()).To(Equal([]string{
+				`[WAR] "flashing failed" {i:0;}`,
+				`[WAR] "Last message repeated 2 times." {repeated:2;}`,
+			}))
+		})
+		It("should return serializer's error", func() {
+			ctrl := gomock.NewController(GinkgoT())
+			defer ctrl.Finish()
+			ms := NewMockSerializer(ctrl)
+			testError := errors.New("Test Error")
+			logRepeated(2, "flashing failed")
+
+			d.backend.Serializer = ms
+			ms.EXPECT().Serialize(gomock.Any()).Return(nil, testError)
+			Expect(d.Flush()).To(Equal(testError))
+		})
+	})
+	Describe("Close", func() {
+		It("should write pending summary", func() {
+			logRepeated(2, "flashing failed")
+			Expect(d.Close()).To(Succeed())
+			Expect(w.Messages()).To(HaveLen(2))
+		})
+	})
+	Describe("timer", func() {
+		It("should pass failure of writing summary to Logger's error handler", func() {
+			ctrl := gomock.NewController(GinkgoT())
+			defer ctrl.Finish()
+			ms := NewMockSerializer(ctrl)
+			h := NewErrorHandlerCount(nil)
+			L.SetErrorHandler(h)
+			logRepeated(2, "flashing failed")
+
+			d.backend.Serializer = ms
+			ms.EXPECT().Serialize(gomock.Any()).Return(nil, errors.New("Test Error"))
+			now = now.Add(time.Minute)
+			d.expire()
+			Expect(h.Errors()).To(Equal(map[string]uint64{"dedup": 1}))
+		})
+		It("should write summary when window passes", func() {
+			d.Close()
+			w = new(writerCollector)
+			d = NewBackendDedup(Backend{
+				Filter:     NewFilterPassAll(),
+				Serializer: newPlainSerializerText(),
+				Writer:     w,
+			}, 10*time.Millisecond)
+			L.AddBackend("dedup", d.Backend())
+			logRepeated(3, "flashing failed")
+
+			Eventually(w.Messages).Should(Equal([]string{
+				`[WAR] "flashing failed" {i:0;}`,
+				`[WAR] "Last message repeated 2 times." {repeated:2;}`,
+			}))
+		})
+	})
+})
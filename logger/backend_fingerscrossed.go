@@ -0,0 +1,265 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"container/list"
+	"sync"
+)
+
+const (
+	// DefaultFingersCrossedSize is the default number of entries buffered in a single scope.
+	DefaultFingersCrossedSize = 100
+	// DefaultFingersCrossedMaxScopes is the default number of scopes buffered at the same time.
+	DefaultFingersCrossedMaxScopes = 1000
+)
+
+// entryRing is a bounded buffer of entries. When it is full, the oldest entry is overwritten.
+type entryRing struct {
+	// entries contains buffered entries.
+	entries []*Entry
+	// next is the index at which the next entry is stored.
+	next int
+	// full is set when buffer wraps around.
+	full bool
+}
+
+// newEntryRing creates a new entryRing with given capacity.
+func newEntryRing(size int) *entryRing {
+	return &entryRing{
+		entries: make([]*Entry, size),
+	}
+}
+
+// push stores entry in the buffer overwriting the oldest entry if buffer is full.
+func (r *entryRing) push(entry *Entry) {
+	r.entries[r.next] = entry
+	r.next++
+	if r.next == len(r.entries) {
+		r.next = 0
+		r.full = true
+	}
+}
+
+// drain returns buffered entries from the oldest to the newest and empties the buffer.
+func (r *entryRing) drain() []*Entry {
+	var ret []*Entry
+	if r.full {
+		ret = append(ret, r.entries[r.next:]...)
+	}
+	ret = append(ret, r.entries[:r.next]...)
+	for i := range r.entries {
+		r.entries[i] = nil
+	}
+	r.next = 0
+	r.full = false
+	return ret
+}
+
+// scopeBuffer is a buffer of a single scope of entries.
+type scopeBuffer struct {
+	// key identifies the scope.
+	key string
+	// ring contains buffered entries of the scope.
+	ring *entryRing
+}
+
+// BackendFingersCrossed buffers entries passed to a wrapped Backend until an entry
+// at or above TriggerLevel arrives. Then buffered history followed by the triggering entry is
+// written to the wrapped backend. It allows to get debug context of failures without writing
+// all debug entries.
+//
+// Entries are buffered in bounded buffers holding up to Size most recent entries. Every scope
+// of entries (see FilterKeyMode) has its own buffer and is flushed independently, e.g. scoping
+// by a job identifier property flushes only history of the failed job and scoping by context
+// (see ContextWithScope) flushes only history of the failed request. Up to MaxScopes scopes
+// are buffered at the same time. When the limit is exceeded, history of the least recently
+// used scope is dropped, so finished scopes that were never discarded do not exhaust memory.
+// History, which has not been triggered, is also dropped on Flush (e.g. on a normal shutdown),
+// unless FlushBuffered is set.
+//
+// BackendFingersCrossed implements Filter interface. Use Backend method to get a Backend
+// that can be registered in Logger.
+type BackendFingersCrossed struct {
+	// TriggerLevel defines the least important level that causes flushing of buffered entries.
+	TriggerLevel Level
+	// Size defines maximum number of entries buffered in a single scope.
+	Size int
+	// KeyMode defines how entries are scoped.
+	KeyMode FilterKeyMode
+	// KeyProperty defines name of property used for scoping in FilterKeyModeProperty mode.
+	KeyProperty string
+	// MaxScopes defines maximum number of scopes buffered at the same time.
+	// Non-positive value means no limit.
+	MaxScopes int
+	// FlushBuffered set to true makes Flush write buffered history of all scopes instead
+	// of dropping it.
+	FlushBuffered bool
+
+	// backend is the wrapped backend.
+	backend Backend
+	// buffers maps scope keys to elements of scopes list.
+	buffers map[string]*list.Element
+	// scopes contains scopeBuffer objects from the most to the least recently used.
+	scopes *list.List
+	// mutex protects buffers and scopes from concurrent access.
+	mutex *sync.Mutex
+}
+
+// NewBackendFingersCrossed creates and returns a new BackendFingersCrossed object wrapping
+// given backend and flushing history of up to DefaultFingersCrossedSize entries when
+// an entry at or above trigger level arrives. Up to DefaultFingersCrossedMaxScopes scopes
+// are buffered.
+func NewBackendFingersCrossed(b Backend, trigger Level) *BackendFingersCrossed {
+	return &BackendFingersCrossed{
+		TriggerLevel: trigger,
+		Size:         DefaultFingersCrossedSize,
+		KeyMode:      FilterKeyModeGlobal,
+		MaxScopes:    DefaultFingersCrossedMaxScopes,
+		backend:      b,
+		buffers:      make(map[string]*list.Element),
+		scopes:       list.New(),
+		mutex:        new(sync.Mutex),
+	}
+}
+
+// Backend returns a Backend using BackendFingersCrossed as its Filter and wrapped backend's
+// Serializer, Writer and Enrichers.
+func (f *BackendFingersCrossed) Backend() Backend {
+	return Backend{
+		Filter:     f,
+		Serializer: f.backend.Serializer,
+		Writer:     f.backend.Writer,
+		Enrichers:  f.backend.Enrichers,
+	}
+}
+
+// wrappedFilter returns Filter of the wrapped backend.
+// It implements filterWrapper interface in BackendFingersCrossed.
+func (f *BackendFingersCrossed) wrappedFilter() Filter {
+	return f.backend.Filter
+}
+
+// write serializes and writes entries to the wrapped backend.
+// It stops on the first error.
+func (f *BackendFingersCrossed) write(entries []*Entry) error {
+	for _, e := range entries {
+		e = f.backend.enrich(e.resolved())
+		buf, err := f.backend.Serializer.Serialize(e)
+		if err != nil {
+			return err
+		}
+		_, err = f.backend.Writer.Write(e.Level, buf)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buffer stores a copy of entry in the buffer of scope identified by key and marks the scope
+// as the most recently used. If there are more than MaxScopes scopes, the least recently used
+// ones are dropped. It must be called with mutex locked.
+func (f *BackendFingersCrossed) buffer(key string, entry *Entry) {
+	el, ok := f.buffers[key]
+	if !ok || len(el.Value.(*scopeBuffer).ring.entries) != f.Size {
+		if ok {
+			f.scopes.Remove(el)
+		}
+		el = f.scopes.PushFront(&scopeBuffer{key: key, ring: newEntryRing(f.Size)})
+		f.buffers[key] = el
+	}
+	f.scopes.MoveToFront(el)
+	el.Value.(*scopeBuffer).ring.push(entry.clone())
+	for f.MaxScopes > 0 && f.scopes.Len() > f.MaxScopes {
+		f.take(f.scopes.Back().Value.(*scopeBuffer).key)
+	}
+}
+
+// take removes buffer of scope identified by key and returns it.
+// It must be called with mutex locked.
+func (f *BackendFingersCrossed) take(key string) (*entryRing, bool) {
+	el, ok := f.buffers[key]
+	if !ok {
+		return nil, false
+	}
+	delete(f.buffers, key)
+	f.scopes.Remove(el)
+	return el.Value.(*scopeBuffer).ring, true
+}
+
+// Verify passes entry to the wrapped backend's Filter and buffers it if its level is below
+// TriggerLevel. Otherwise it writes buffered history of entry's scope and accepts the entry.
+// Failure of writing history is passed to Logger's error handler and the entry is still accepted.
+// It implements Filter interface in BackendFingersCrossed type.
+func (f *BackendFingersCrossed) Verify(entry *Entry) (bool, error) {
+	if entry == nil {
+		return false, ErrInvalidEntry
+	}
+	pass, err := f.backend.Filter.Verify(entry)
+	if err != nil || !pass {
+		return pass, err
+	}
+
+	key := filterKey(f.KeyMode, f.KeyProperty, entry)
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if entry.Level > f.TriggerLevel {
+		if f.Size > 0 {
+			f.buffer(key, entry)
+		}
+		return false, nil
+	}
+	r, ok := f.take(key)
+	if !ok {
+		return true, nil
+	}
+	if err = f.write(r.drain()); err != nil {
+		// Failure of writing history must not cause loss of the triggering entry.
+		entry.Logger.handleFilterError(f, entry, err)
+	}
+	return true, nil
+}
+
+// Flush drops buffered entries of all scopes, as none of them has been triggered. If FlushBuffered
+// is set, they are written to the wrapped backend instead, starting from the least recently used
+// scope. Filter and Writer of the wrapped backend are flushed by Logger's Flush like those
+// of any backend.
+func (f *BackendFingersCrossed) Flush() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if !f.FlushBuffered {
+		f.buffers = make(map[string]*list.Element)
+		f.scopes.Init()
+		return nil
+	}
+	for f.scopes.Len() > 0 {
+		r, _ := f.take(f.scopes.Back().Value.(*scopeBuffer).key)
+		if err := f.write(r.drain()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Discard drops buffered entries of a single scope, e.g. when a job has finished successfully.
+// In FilterKeyModeContext mode the key of context's scope is returned by ContextScope.
+func (f *BackendFingersCrossed) Discard(key string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.take(key)
+}
@@ -0,0 +1,234 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"context"
+	"errors"
+
+	gomock "github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BackendFingersCrossed", func() {
+	var (
+		w *writerCollector
+		f *BackendFingersCrossed
+		L *Logger
+	)
+
+	BeforeEach(func() {
+		w = new(writerCollector)
+		f = NewBackendFingersCrossed(Backend{
+			Filter:     NewFilterPassAll(),
+			Serializer: newPlainSerializerText(),
+			Writer:     w,
+		}, ErrLevel)
+		L = NewLogger()
+		L.SetThreshold(DebugLevel)
+		L.AddBackend("fingerscrossed", f.Backend())
+	})
+
+	Describe("NewBackendFingersCrossed", func() {
+		It("should create a new object with default configuration", func() {
+			Expect(f).NotTo(BeNil())
+			Expect(f.TriggerLevel).To(Equal(ErrLevel))
+			Expect(f.Size).To(Equal(DefaultFingersCrossedSize))
+			Expect(f.KeyMode).To(Equal(FilterKeyModeGlobal))
+			Expect(f.MaxScopes).To(Equal(DefaultFingersCrossedMaxScopes))
+			Expect(f.FlushBuffered).To(BeFalse())
+		})
+	})
+	Describe("Verify", func() {
+		It("should buffer entries until trigger level", func() {
+			L.Debug("one")
+			L.Info("two")
+			L.Warning("three")
+			Expect(w.Messages()).To(BeEmpty())
+
+			L.Error("failed")
+			Expect(w.Messages()).To(Equal([]string{
+				`[DEB] one `,
+				`[INF] two `,
+				`[WAR] three `,
+				`[ERR] failed `,
+			}))
+
+			L.Critical("again")
+			Expect(w.Messages()).To(HaveLen(5))
+		})
+		It("should keep only the most recent entries", func() {
+			f.Size = 2
+			L.Debug("one")
+			L.Debug("two")
+			L.Debug("three")
+			L.Alert("failed")
+			Expect(w.Messages()).To(Equal([]string{
+				`[DEB] two `,
+				`[DEB] three `,
+				`[ALE] failed `,
+			}))
+		})
+		It("should flush only history of triggering entry's scope", func() {
+			f.KeyMode = FilterKeyModeProperty
+			f.KeyProperty = "job_id"
+			L.WithProperty("job_id", 1).Debug("one")
+			L.WithProperty("job_id", 2).Debug("two")
+			L.WithProperty("job_id", 1).Error("failed")
+			Expect(w.Messages()).To(Equal([]string{
+				`[DEB] one {job_id:1;}`,
+				`[ERR] failed {job_id:1;}`,
+			}))
+		})
+		It("should flush only history of triggering entry's context", func() {
+			f.KeyMode = FilterKeyModeContext
+			ctx1 := ContextWithScope(context.Background())
+			ctx2 := ContextWithScope(context.Background())
+			L.WithContext(ctx1).Debug("one")
+			L.WithContext(ctx2).Debug("two")
+			L.WithContext(ctx1).Error("failed")
+			Expect(w.Messages()).To(Equal([]string{`[DEB] one `, `[ERR] failed `}))
+
+			f.Discard(ContextScope(ctx2))
+			L.WithContext(ctx2).Error("failed")
+			Expect(w.Messages()).To(HaveLen(3))
+		})
+		It("should drop history of the least recently used scope", func() {
+			f.KeyMode = FilterKeyModeProperty
+			f.KeyProperty = "job_id"
+			f.MaxScopes = 2
+			f.FlushBuffered = true
+			L.WithProperty("job_id", 1).Debug("one")
+			L.WithProperty("job_id", 2).Debug("two")
+			L.WithProperty("job_id", 1).Debug("three")
+			L.WithProperty("job_id", 3).Debug("four")
+			Expect(f.Flush()).To(Succeed())
+			Expect(w.Messages()).To(Equal([]string{
+				`[DEB] one {job_id:1;}`,
+				`[DEB] three {job_id:1;}`,
+				`[DEB] four {job_id:3;}`,
+			}))
+		})
+		It("should buffer copies of entries", func() {
+			e := &Entry{Level: DebugLevel, Properties: Properties{"a": 1}}
+			pass, err := f.Verify(e)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pass).To(BeFalse())
+			e.Properties["a"] = 2
+			L.Error("failed")
+			Expect(w.Messages()[0]).To(Equal(`[DEB] "" {a:1;}`))
+		})
+		It("should evaluate lazy values of buffered entries only when writing them", func() {
+			calls := 0
+			L.WithProperty("a", func() interface{} {
+				calls++
+				return calls
+			}).Debug("one")
+			Expect(calls).To(BeZero())
+			L.Error("failed")
+			Expect(calls).To(Equal(1))
+			Expect(w.Messages()[0]).To(Equal(`[DEB] one {a:1;}`))
+		})
+		It("should run wrapped backend's enrichers on buffered entries", func() {
+			f = NewBackendFingersCrossed(Backend{
+				Filter:     NewFilterPassAll(),
+				Serializer: newPlainSerializerText(),
+				Writer:     w,
+				Enrichers:  []Enricher{EnricherFields{String("env", "test")}},
+			}, ErrLevel)
+			L.AddBackend("fingerscrossed", f.Backend())
+			L.Debug("one")
+			L.Error("failed")
+			Expect(w.Messages()).To(Equal([]string{
+				`[DEB] one {env:test;}`,
+				`[ERR] failed {env:test;}`,
+			}))
+		})
+		It("should drop entries if size is not positive", func() {
+			f.Size = 0
+			L.Debug("one")
+			L.Error("failed")
+			Expect(w.Messages()).To(Equal([]string{`[ERR] failed `}))
+		})
+		It("should write triggering entry if writing history fails", func() {
+			ctrl := gomock.NewController(GinkgoT())
+			defer ctrl.Finish()
+			mw := NewMockWriter(ctrl)
+			h := NewErrorHandlerCount(nil)
+			L.SetErrorHandler(h)
+			f.backend.Writer = mw
+
+			L.Debug("one")
+			mw.EXPECT().Write(DebugLevel, gomock.Any()).Return(0, errors.New("Test Error"))
+			L.Error("failed")
+			Expect(h.Errors()).To(Equal(map[string]uint64{"fingerscrossed": 1}))
+			Expect(w.Messages()).To(Equal([]string{`[ERR] failed `}))
+		})
+		It("should return error for nil entry", func() {
+			pass, err := f.Verify(nil)
+			Expect(err).To(Equal(ErrInvalidEntry))
+			Expect(pass).To(BeFalse())
+		})
+	})
+	Describe("Flush", func() {
+		It("should drop buffered entries by default", func() {
+			L.Debug("one")
+			Expect(L.Flush()).To(Succeed())
+			L.Error("failed")
+			Expect(w.Messages()).To(Equal([]string{`[ERR] failed `}))
+		})
+		It("should write all buffered entries if requested", func() {
+			f.FlushBuffered = true
+			L.Debug("one")
+			Expect(f.Flush()).To(Succeed())
+			Expect(f.Flush()).To(Succeed())
+			Expect(w.Messages()).To(Equal([]string{`[DEB] one `}))
+		})
+		It("should flush the wrapped writer when Logger is flushed", func() {
+			fc := new(flushCounter)
+			f = NewBackendFingersCrossed(Backend{
+				Filter:     NewFilterPassAll(),
+				Serializer: newPlainSerializerText(),
+				Writer:     fc,
+			}, ErrLevel)
+			L.AddBackend("fingerscrossed", f.Backend())
+			Expect(L.Flush()).To(Succeed())
+			Expect(fc.flushed).To(Equal(1))
+		})
+	})
+	Describe("Discard", func() {
+		It("should drop buffered entries of a scope", func() {
+			L.Debug("one")
+			f.Discard("")
+			L.Error("failed")
+			Expect(w.Messages()).To(Equal([]string{`[ERR] failed `}))
+		})
+	})
+	Describe("entryRing", func() {
+		It("should return entries from the oldest", func() {
+			r := newEntryRing(3)
+			Expect(r.drain()).To(BeEmpty())
+			entries := []*Entry{{Message: "1"}, {Message: "2"}, {Message: "3"}, {Message: "4"}}
+			for _, e := range entries {
+				r.push(e)
+			}
+			Expect(r.drain()).To(Equal(entries[1:]))
+			Expect(r.drain()).To(BeEmpty())
+		})
+	})
+})
@@ -104,4 +104,96 @@ var _ = Describe("Backend", func() {
 			Expect(err).To(Equal(testError))
 		})
 	})
+	Describe("serialize", func() {
+		It("should use pooled buffer with AppendSerializer", func() {
+			mb.Serializer = NewSerializerJSON()
+			buf, pooled, err := mb.serialize(e)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pooled).NotTo(BeNil())
+			Expect(buf).To(Equal(*pooled))
+			expected, err := NewSerializerJSON().Serialize(e)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(buf).To(Equal(expected))
+			putBuffer(pooled)
+		})
+		It("should not use pooled buffer with plain Serializer", func() {
+			ms.EXPECT().Serialize(e).Return(buf, nil)
+			ret, pooled, err := mb.serialize(e)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pooled).To(BeNil())
+			Expect(ret).To(Equal(buf))
+		})
+		It("should return error of AppendSerializer", func() {
+			mb.Serializer = NewSerializerText()
+			ret, pooled, err := mb.serialize(nil)
+			Expect(err).To(Equal(ErrInvalidEntry))
+			Expect(pooled).To(BeNil())
+			Expect(ret).To(BeNil())
+		})
+	})
+	Describe("processStats", func() {
+		var stats *backendStats
+		BeforeEach(func() {
+			stats = newBackendStats()
+		})
+		It("should count accepted entries and written bytes", func() {
+			gomock.InOrder(
+				mf.EXPECT().Verify(e).Return(true, nil),
+				ms.EXPECT().Serialize(e).Return(buf, nil),
+				mw.EXPECT().Write(e.Level, buf),
+			)
+
+			err := mb.processStats(e, stats)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stats.snapshot().Counters).To(Equal(Counters{
+				Accepted: 1,
+				Bytes:    uint64(len(buf)),
+			}))
+		})
+		It("should count filtered entries", func() {
+			mf.EXPECT().Verify(e).Return(false, nil)
+
+			err := mb.processStats(e, stats)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stats.snapshot().Counters).To(Equal(Counters{Filtered: 1}))
+		})
+		It("should count failures", func() {
+			mf.EXPECT().Verify(e).Return(false, testError)
+			gomock.InOrder(
+				mf.EXPECT().Verify(e).Return(true, nil),
+				ms.EXPECT().Serialize(e).Return(nil, testError),
+			)
+			gomock.InOrder(
+				mf.EXPECT().Verify(e).Return(true, nil),
+				ms.EXPECT().Serialize(e).Return(buf, nil),
+				mw.EXPECT().Write(e.Level, buf).Return(0, testError),
+			)
+
+			for i := 0; i < 3; i++ {
+				err := mb.processStats(e, stats)
+				Expect(err).To(Equal(testError))
+			}
+			Expect(stats.snapshot().Counters).To(Equal(Counters{Errors: 3}))
+		})
+	})
+	Describe("flush", func() {
+		It("should flush all elements implementing Flusher", func() {
+			w := &flushCounter{err: testError}
+			mb.Writer = w
+			Expect(mb.flush()).To(Equal(testError))
+			Expect(w.flushed).To(Equal(1))
+		})
+		It("should do nothing if no element implements Flusher", func() {
+			Expect(mb.flush()).To(Succeed())
+		})
+	})
+	Describe("usesCallContext", func() {
+		It("should return true if Filter or Serializer uses call context", func() {
+			Expect(mb.usesCallContext()).To(BeTrue())
+			mb.Filter = NewFilterPassAll()
+			Expect(mb.usesCallContext()).To(BeTrue())
+			mb.Serializer = newPlainSerializerText()
+			Expect(mb.usesCallContext()).To(BeFalse())
+		})
+	})
 })
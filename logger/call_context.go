@@ -19,6 +19,7 @@ package logger
 import (
 	"runtime"
 	"strings"
+	"sync"
 )
 
 // CallContext defines log creation source code context.
@@ -31,13 +32,142 @@ type CallContext struct {
 	Function string `json:"function"`
 }
 
+// funcName returns fully qualified name of the function in the format of runtime.Frame's Function,
+// e.g. "github.com/SamsungSLAV/slav/logger.(*Logger).Info".
+func (c *CallContext) funcName() string {
+	if len(c.Type) > 0 {
+		return c.Package + "." + c.Type + "." + c.Function
+	}
+	return c.Package + "." + c.Function
+}
+
+// CallContextUser is implemented by Filters and Serializers to declare whether they use call
+// context of entries. Filters and Serializers not implementing it are assumed to use it.
+// Call context is captured only if it is used by at least one of Logger's backends.
+type CallContextUser interface {
+	// UsesCallContext returns true if call context of entries is used.
+	UsesCallContext() bool
+}
+
+// usesCallContext returns true if v uses call context of entries.
+func usesCallContext(v interface{}) bool {
+	u, ok := v.(CallContextUser)
+	return !ok || u.UsesCallContext()
+}
+
+// maxCallContextDepth limits number of stack frames inspected while skipping helper functions.
+const maxCallContextDepth = 32
+
+// callSite is a call context resolved from a program counter.
+type callSite struct {
+	// function is the full name of the function.
+	function string
+	// ctx is the call context.
+	ctx CallContext
+}
+
+// callContextCache maps program counters to call sites resolved from them,
+// so function names of repeated call sites are parsed only once.
+var callContextCache sync.Map
+
+// resolveCallSite returns call site of given program counter.
+func resolveCallSite(pc uintptr) *callSite {
+	if cached, ok := callContextCache.Load(pc); ok {
+		return cached.(*callSite)
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	site := &callSite{
+		function: frame.Function,
+		ctx:      newCallContext(frame.File, frame.Line, frame.Function),
+	}
+	callContextCache.Store(pc, site)
+	return site
+}
+
+// skippedPackages contains packages which are never reported as callers: Go runtime (e.g. while
+// panicking) and standard logging packages bridged with LineWriter and SlogHandler.
+var skippedPackages = map[string]bool{
+	"runtime":  true,
+	"log":      true,
+	"log/slog": true,
+}
+
+// skipFrame returns true if call site should be skipped while looking for the caller.
+// Frames of helper functions and skippedPackages are skipped.
+func skipFrame(site *callSite) bool {
+	return isHelper(site.function) || skippedPackages[site.ctx.Package]
+}
+
+// getCallContext returns call context of the function depth frames above the caller.
+// Functions marked with Helper and Go runtime are skipped.
 func getCallContext(depth int) *CallContext {
-	pc, file, line, ok := runtime.Caller(depth + 1)
-	if !ok {
+	pc := getCallerPC(depth + 1)
+	if pc == 0 {
 		return nil
 	}
+	ret := resolveCallSite(pc).ctx
+	return &ret
+}
+
+// getCallerPC returns program counter of the function depth frames above the caller
+// or 0 if stack is too short. Functions marked with Helper and Go runtime are skipped.
+func getCallerPC(depth int) uintptr {
+	frames := 1
+	if helpersMarked() {
+		frames = maxCallContextDepth
+	}
+	return findCallerPC(depth+1, frames)
+}
+
+// findCallerPC returns program counter of the first function not skipped by skipFrame starting
+// depth frames above the caller. Up to frames stack frames are inspected. If all of them are
+// skipped, program counter of the last one is returned. It returns 0 if stack is too short.
+func findCallerPC(depth, frames int) uintptr {
+	var pcs [maxCallContextDepth]uintptr
+	if frames > len(pcs) {
+		frames = len(pcs)
+	}
+	n := runtime.Callers(depth+2, pcs[:frames])
+	if n < 1 {
+		return 0
+	}
+
+	var pc uintptr
+	for _, pc = range pcs[:n] {
+		if !skipFrame(resolveCallSite(pc)) {
+			break
+		}
+	}
+	return pc
+}
+
+// maxStackDepth limits number of frames in stack traces.
+const maxStackDepth = 64
 
-	ret := new(CallContext)
+// getStack returns stack trace starting at the function depth frames above the caller.
+// Helper functions and frames of Go runtime at the top of the stack and frames of Go runtime
+// at its bottom are trimmed.
+func getStack(depth int) []CallContext {
+	var pcs [maxStackDepth]uintptr
+	n := runtime.Callers(depth+2, pcs[:])
+
+	stack := make([]CallContext, 0, n)
+	for _, pc := range pcs[:n] {
+		site := resolveCallSite(pc)
+		if len(stack) == 0 && skipFrame(site) {
+			continue
+		}
+		stack = append(stack, site.ctx)
+	}
+	for len(stack) > 0 && stack[len(stack)-1].Package == "runtime" {
+		stack = stack[:len(stack)-1]
+	}
+	return stack
+}
+
+// newCallContext parses file path and function name into CallContext.
+func newCallContext(file string, line int, function string) CallContext {
+	var ret CallContext
 
 	fi := strings.LastIndex(file, "/")
 	if fi != -1 {
@@ -47,8 +177,6 @@ func getCallContext(depth int) *CallContext {
 
 	ret.Line = line
 
-	function := runtime.FuncForPC(pc).Name()
-
 	si := strings.LastIndex(function, "/")
 	// If there is no '/' move to index 0, otherwise move after '/'.
 	si++
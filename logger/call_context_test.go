@@ -19,6 +19,7 @@ package logger
 import (
 	"go/build"
 	P "path"
+	"runtime"
 
 	. "github.com/onsi/ginkgo"
 	T "github.com/onsi/ginkgo/extensions/table"
@@ -43,6 +44,11 @@ func (c *CallContextTest) byPointer(depth int) *CallContext {
 	return c.byValue(depth)
 }
 
+func stackHelper() []CallContext {
+	Helper()
+	return getStack(0)
+}
+
 var _ = Describe("CallContext", func() {
 	const (
 		thisFile = "call_context_test.go"
@@ -65,14 +71,66 @@ var _ = Describe("CallContext", func() {
 				Expect(ctx.Type).To(Equal(typ))
 				Expect(ctx.Function).To(Equal(function))
 			},
-			T.Entry("foo", 0, 29, "", "foo"),
-			T.Entry("bar", 1, 33, "", "bar"),
-			T.Entry("byValue", 2, 39, "CallContextTest", "byValue"),
-			T.Entry("byPointer", 3, 43, "(*CallContextTest)", "byPointer"),
+			T.Entry("foo", 0, 30, "", "foo"),
+			T.Entry("bar", 1, 34, "", "bar"),
+			T.Entry("byValue", 2, 40, "CallContextTest", "byValue"),
+			T.Entry("byPointer", 3, 44, "(*CallContextTest)", "byPointer"),
 		)
 		It("should return nil context if reaching to deep", func() {
 			ctx := getCallContext(100000)
 			Expect(ctx).To(BeNil())
 		})
 	})
+	Describe("getCallContext cache", func() {
+		It("should return equal copies of cached context", func() {
+			var ctxs [2]*CallContext
+			for i := range ctxs {
+				ctxs[i] = foo(0)
+			}
+			Expect(ctxs[0]).To(Equal(ctxs[1]))
+			Expect(ctxs[0]).NotTo(BeIdenticalTo(ctxs[1]))
+
+			ctxs[0].Line = 0
+			Expect(foo(0).Line).To(Equal(30))
+		})
+	})
+	Describe("getStack", func() {
+		It("should return trimmed stack starting at the caller", func() {
+			_, _, line, _ := runtime.Caller(0)
+			stack := getStack(0)
+			Expect(stack).NotTo(BeEmpty())
+			Expect(stack[0].File).To(Equal(thisFile))
+			Expect(stack[0].Line).To(Equal(line + 1))
+			Expect(stack[len(stack)-1].Package).NotTo(Equal("runtime"))
+		})
+		It("should trim helper functions at the top", func() {
+			stack := stackHelper()
+			Expect(stack).NotTo(BeEmpty())
+			Expect(stack[0].Function).NotTo(Equal("stackHelper"))
+		})
+	})
+	Describe("newCallContext", func() {
+		T.DescribeTable("should parse file path and function name",
+			func(file, function string, expected CallContext) {
+				Expect(newCallContext(file, 7, function)).To(Equal(expected))
+			},
+			T.Entry("function", "/a/b/c.go", "example.com/p.f",
+				CallContext{Path: "/a/b/", File: "c.go", Line: 7, Package: "example.com/p",
+					Function: "f"}),
+			T.Entry("method", "/c.go", "p.T.f",
+				CallContext{Path: "/", File: "c.go", Line: 7, Package: "p", Type: "T",
+					Function: "f"}),
+			T.Entry("no path", "c.go", "p.f",
+				CallContext{Line: 7, Package: "p", Function: "f"}),
+		)
+	})
+	Describe("usesCallContext", func() {
+		It("should assume that call context is used if interface is not implemented", func() {
+			Expect(usesCallContext(struct{}{})).To(BeTrue())
+		})
+		It("should ask CallContextUser", func() {
+			Expect(usesCallContext(NewFilterPassAll())).To(BeFalse())
+			Expect(usesCallContext(NewSerializerText())).To(BeTrue())
+		})
+	})
 })
@@ -0,0 +1,190 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// CommandProperty defines key of field holding name of the command run with Command.
+	CommandProperty = "command"
+	// CommandPIDProperty defines key of field holding process ID of the command.
+	CommandPIDProperty = "command_pid"
+	// StreamProperty defines key of field holding name of the stream ("stdout" or "stderr")
+	// of the command, which produced the logged line.
+	StreamProperty = "stream"
+	// ExitCodeProperty defines key of field holding exit code of the command.
+	ExitCodeProperty = "exit_code"
+	// DurationProperty defines key of field holding duration of the command.
+	DurationProperty = "duration"
+)
+
+// Names of streams of the command used as values of StreamProperty.
+const (
+	StreamStdout = "stdout"
+	StreamStderr = "stderr"
+)
+
+// Command runs exec.Cmd logging its output. Every line written by the command to stdout
+// and stderr is logged as a separate entry with level of the stream. When the command
+// finishes, an entry with its exit code and duration is logged. All entries have fields
+// with name and PID of the command. Call context of the entries is the caller of Start or Run.
+// Lines longer than MaxLineLength (e.g. progress reports ended with '\r' only) are logged
+// in parts.
+type Command struct {
+	// Cmd is the command to run. Its Stdout and Stderr must not be set.
+	Cmd *exec.Cmd
+	// Logger logs output and exit status of the command.
+	Logger *Logger
+	// StdoutLevel is the level of lines written to stdout.
+	StdoutLevel Level
+	// StderrLevel is the level of lines written to stderr.
+	StderrLevel Level
+	// ExitLevel is the level of the final entry of the successful command.
+	ExitLevel Level
+	// FailureLevel is the level of the final entry of the command, which failed to start
+	// or exited with error.
+	FailureLevel Level
+	// MaxLineLength limits length of logged lines in bytes. If it is not positive,
+	// DefaultMaxLineLength is used.
+	MaxLineLength int
+	// pc is the program counter of the caller used as call context.
+	pc uintptr
+	// fields are logged with every entry.
+	fields []Field
+	// started is the time the command was started.
+	started time.Time
+	// readers waits for goroutines reading output of the command.
+	readers sync.WaitGroup
+}
+
+// NewCommand creates and returns a new Command running cmd and logging its output to l.
+// Stdout is logged with InfoLevel, stderr with WarningLevel, exit of the command with InfoLevel
+// and failure of the command with ErrLevel. Lines are limited to DefaultMaxLineLength bytes.
+func NewCommand(l *Logger, cmd *exec.Cmd) *Command {
+	return &Command{
+		Cmd:           cmd,
+		Logger:        l,
+		StdoutLevel:   InfoLevel,
+		StderrLevel:   WarningLevel,
+		ExitLevel:     InfoLevel,
+		FailureLevel:  ErrLevel,
+		MaxLineLength: DefaultMaxLineLength,
+	}
+}
+
+// Start starts the command and goroutines logging its output. Wait must be called to release
+// resources of the command and log its exit status.
+func (c *Command) Start() error {
+	return c.start(getCallerPC(1))
+}
+
+// Wait waits for the command to exit and its output to be logged. Then it logs exit code
+// and duration of the command. Returned error is the one of exec.Cmd's Wait.
+func (c *Command) Wait() error {
+	c.readers.Wait()
+	err := c.Cmd.Wait()
+	fields := []Field{Duration(DurationProperty, time.Since(c.started))}
+	if c.Cmd.ProcessState != nil {
+		fields = append(fields, Int(ExitCodeProperty, c.Cmd.ProcessState.ExitCode()))
+	}
+	e := c.entry().WithFields(fields...)
+	if err != nil {
+		e.WithError(err).Log(c.FailureLevel, "Command failed.")
+		return err
+	}
+	e.Log(c.ExitLevel, "Command finished.")
+	return nil
+}
+
+// Run starts the command and waits for it to finish.
+func (c *Command) Run() error {
+	if err := c.start(getCallerPC(1)); err != nil {
+		return err
+	}
+	return c.Wait()
+}
+
+// start starts the command with output logged with call context of given program counter.
+// Failure of starting the command is logged.
+func (c *Command) start(pc uintptr) error {
+	c.pc = pc
+	c.fields = []Field{String(CommandProperty, filepath.Base(c.Cmd.Path))}
+	stdout, err := c.Cmd.StdoutPipe()
+	if err != nil {
+		return c.startFailed(err)
+	}
+	stderr, err := c.Cmd.StderrPipe()
+	if err != nil {
+		return c.startFailed(err)
+	}
+	c.started = time.Now()
+	if err = c.Cmd.Start(); err != nil {
+		return c.startFailed(err)
+	}
+	c.fields = append(c.fields, Int(CommandPIDProperty, c.Cmd.Process.Pid))
+	c.readers.Add(2)
+	go c.read(stdout, StreamStdout, c.StdoutLevel)
+	go c.read(stderr, StreamStderr, c.StderrLevel)
+	return nil
+}
+
+// startFailed logs failure of starting the command and returns err.
+func (c *Command) startFailed(err error) error {
+	c.entry().WithError(err).Log(c.FailureLevel, "Command failed to start.")
+	return err
+}
+
+// readBufferSize is the size of buffer used for reading output of the command.
+const readBufferSize = 4096
+
+// read logs lines read from r with given level until end of stream. Empty lines are omitted.
+func (c *Command) read(r io.Reader, stream string, level Level) {
+	defer c.readers.Done()
+	emit := func(line string) {
+		line = strings.TrimRight(line, "\r")
+		if line != "" {
+			c.entry().WithFields(String(StreamProperty, stream)).Log(level, line)
+		}
+	}
+	var lines lineBuffer
+	buf := make([]byte, readBufferSize)
+	for {
+		n, err := r.Read(buf)
+		lines.write(buf[:n], c.MaxLineLength, emit)
+		if err != nil {
+			lines.flush(emit)
+			return
+		}
+	}
+}
+
+// entry returns a new entry with fields of the command and its call context.
+func (c *Command) entry() *Entry {
+	return c.Logger.WithFields(c.fields...).WithCaller(c.pc)
+}
+
+// Command returns a new Command running cmd and logging its output.
+func (l *Logger) Command(cmd *exec.Cmd) *Command {
+	return NewCommand(l, cmd)
+}
@@ -0,0 +1,141 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"runtime"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Command", func() {
+	const thisFile = "command_test.go"
+	var (
+		L *Logger
+		f *filterCollector
+	)
+
+	BeforeEach(func() {
+		L = NewLogger()
+		L.SetThreshold(DebugLevel)
+		f = new(filterCollector)
+		L.AddBackend("collector", Backend{
+			Filter:     f,
+			Serializer: NewSerializerJSON(),
+			Writer:     new(writerCollector),
+		})
+	})
+
+	// value returns value of field or property with given key.
+	value := func(e *Entry, key string) interface{} {
+		v, _ := e.property(key)
+		return v
+	}
+	// lines returns levels and messages of entries logged for given stream.
+	lines := func(stream string) (ret []string) {
+		for _, e := range f.Entries() {
+			if value(e, StreamProperty) == stream {
+				ret = append(ret, fmt.Sprintf("%s %s", e.Level, e.Message))
+			}
+		}
+		return ret
+	}
+	// last returns the last logged entry.
+	last := func() *Entry {
+		entries := f.Entries()
+		Expect(entries).NotTo(BeEmpty())
+		return entries[len(entries)-1]
+	}
+
+	It("should create a new object with default configuration", func() {
+		cmd := exec.Command("true")
+		c := L.Command(cmd)
+		Expect(c.Cmd).To(Equal(cmd))
+		Expect(c.Logger).To(Equal(L))
+		Expect(c.StdoutLevel).To(Equal(InfoLevel))
+		Expect(c.StderrLevel).To(Equal(WarningLevel))
+		Expect(c.ExitLevel).To(Equal(InfoLevel))
+		Expect(c.FailureLevel).To(Equal(ErrLevel))
+		Expect(c.MaxLineLength).To(Equal(DefaultMaxLineLength))
+	})
+	It("should log lines of both streams and exit status", func() {
+		c := L.Command(exec.Command("sh", "-c",
+			"echo first; echo error >&2; printf 'second\\r\\n\\nthird'"))
+		c.StdoutLevel = NoticeLevel
+		_, _, line, _ := runtime.Caller(0)
+		Expect(c.Run()).To(Succeed())
+
+		Expect(lines(StreamStdout)).To(Equal([]string{
+			"notice first", "notice second", "notice third",
+		}))
+		Expect(lines(StreamStderr)).To(Equal([]string{"warning error"}))
+		for _, e := range f.Entries() {
+			Expect(value(e, CommandProperty)).To(Equal("sh"))
+			Expect(value(e, CommandPIDProperty)).To(Equal(c.Cmd.Process.Pid))
+			Expect(e.CallContext.File).To(Equal(thisFile))
+			Expect(e.CallContext.Line).To(Equal(line + 1))
+		}
+		Expect(markedAsHelper((*Command).Run)).To(BeFalse())
+		e := last()
+		Expect(e.Level).To(Equal(InfoLevel))
+		Expect(e.Message).To(Equal("Command finished."))
+		Expect(value(e, ExitCodeProperty)).To(Equal(0))
+		Expect(value(e, DurationProperty)).To(BeNumerically(">", 0))
+	})
+	It("should log parts of lines longer than the limit", func() {
+		c := L.Command(exec.Command("sh", "-c", "printf '10%%\r20%%\r'; printf 'abcdef\n'"))
+		c.MaxLineLength = 4
+		Expect(c.Run()).To(Succeed())
+
+		Expect(lines(StreamStdout)).To(Equal([]string{
+			"info 10%", "info 20%", "info abcd", "info ef",
+		}))
+	})
+	It("should log failure of the command", func() {
+		c := L.Command(exec.Command("sh", "-c", "exit 3"))
+		Expect(c.Start()).To(Succeed())
+		err := c.Wait()
+		Expect(err).To(HaveOccurred())
+
+		e := last()
+		Expect(e.Level).To(Equal(ErrLevel))
+		Expect(e.Message).To(Equal("Command failed."))
+		Expect(value(e, ExitCodeProperty)).To(Equal(3))
+		Expect(e.Properties).To(HaveKeyWithValue(ErrorProperty, NewErrorValue(err)))
+	})
+	It("should log failure of starting the command", func() {
+		_, _, line, _ := runtime.Caller(0)
+		err := L.Command(exec.Command("/nonexistent/command")).Run()
+		Expect(err).To(HaveOccurred())
+
+		Expect(f.Entries()).To(HaveLen(1))
+		e := last()
+		Expect(e.Level).To(Equal(ErrLevel))
+		Expect(e.Message).To(Equal("Command failed to start."))
+		Expect(value(e, CommandProperty)).To(Equal("command"))
+		Expect(e.CallContext.Line).To(Equal(line + 1))
+	})
+	It("should fail to start if output is already redirected", func() {
+		cmd := exec.Command("true")
+		cmd.Stdout = new(bytes.Buffer)
+		Expect(L.Command(cmd).Start()).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,103 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+)
+
+// contextKey is the type of keys of values stored by logger in context.Context.
+type contextKey int
+
+const (
+	// loggerContextKey is the key of Logger stored in context.
+	loggerContextKey contextKey = iota
+	// propertiesContextKey is the key of Properties stored in context.
+	propertiesContextKey
+	// traceContextKey is the key of TraceContext stored in context.
+	traceContextKey
+	// scopeContextKey is the key of scope stored in context.
+	scopeContextKey
+)
+
+// lastScope is the identifier of the last scope created with ContextWithScope.
+var lastScope uint64
+
+// NewContext returns a copy of ctx carrying l.
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey, l)
+}
+
+// FromContext returns Logger carried by ctx or the default logger if ctx carries none.
+func FromContext(ctx context.Context) *Logger {
+	if l, ok := ctx.Value(loggerContextKey).(*Logger); ok {
+		return l
+	}
+	return defaultLogger
+}
+
+// ContextWithProperties returns a copy of ctx carrying props in addition to properties
+// already carried by ctx. Entries created with WithContext have these properties.
+func ContextWithProperties(ctx context.Context, props Properties) context.Context {
+	parent := ContextProperties(ctx)
+	merged := make(Properties, len(parent)+len(props))
+	for k, v := range parent {
+		merged[k] = v
+	}
+	for k, v := range props {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, propertiesContextKey, merged)
+}
+
+// ContextProperties returns properties carried by ctx. The returned map must not be modified.
+func ContextProperties(ctx context.Context) Properties {
+	props, _ := ctx.Value(propertiesContextKey).(Properties)
+	return props
+}
+
+// ContextWithScope returns a copy of ctx carrying a new unique scope. Entries created
+// with WithContext are grouped by the scope in FilterKeyModeContext mode, e.g. history
+// of a single job or request is buffered by BackendFingersCrossed.
+func ContextWithScope(ctx context.Context) context.Context {
+	scope := strconv.FormatUint(atomic.AddUint64(&lastScope, 1), 10)
+	return context.WithValue(ctx, scopeContextKey, scope)
+}
+
+// ContextScope returns the scope carried by ctx or empty string if it carries none. It is
+// the key of the group of entries in FilterKeyModeContext mode.
+func ContextScope(ctx context.Context) string {
+	scope, _ := ctx.Value(scopeContextKey).(string)
+	return scope
+}
+
+// WithContext creates a log message with properties and scope carried by ctx.
+func (l *Logger) WithContext(ctx context.Context) *Entry {
+	e := l.newEntry().WithProperties(ContextProperties(ctx))
+	e.scope = ContextScope(ctx)
+	return e
+}
+
+// WithContext creates a log message with properties and scope carried by ctx in Logger
+// carried by ctx (or default logger). It is meant for request-scoped logging:
+//
+//	logger.WithContext(r.Context()).Info("job scheduled")
+func WithContext(ctx context.Context) *Entry {
+	return FromContext(ctx).WithContext(ctx)
+}
@@ -0,0 +1,83 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Context", func() {
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	Describe("FromContext", func() {
+		It("should return logger carried by context", func() {
+			L := NewLogger()
+			Expect(FromContext(NewContext(ctx, L))).To(BeIdenticalTo(L))
+		})
+		It("should return default logger if context carries none", func() {
+			Expect(FromContext(ctx)).To(BeIdenticalTo(defaultLogger))
+		})
+	})
+	Describe("ContextWithProperties", func() {
+		It("should merge properties without changing parent context", func() {
+			parent := ContextWithProperties(ctx, Properties{"a": 1, "b": 2})
+			child := ContextWithProperties(parent, Properties{"b": 3, "c": 4})
+			Expect(ContextProperties(parent)).To(Equal(Properties{"a": 1, "b": 2}))
+			Expect(ContextProperties(child)).To(Equal(Properties{"a": 1, "b": 3, "c": 4}))
+		})
+		It("should return no properties for context without them", func() {
+			Expect(ContextProperties(ctx)).To(BeEmpty())
+		})
+	})
+	Describe("ContextWithScope", func() {
+		It("should create unique scopes", func() {
+			ctx1 := ContextWithScope(ctx)
+			ctx2 := ContextWithScope(ctx1)
+			Expect(ContextScope(ctx1)).NotTo(BeEmpty())
+			Expect(ContextScope(ctx2)).NotTo(Equal(ContextScope(ctx1)))
+			Expect(ContextScope(ctx)).To(BeEmpty())
+		})
+	})
+	Describe("WithContext", func() {
+		It("should create entry with properties of context", func() {
+			L := NewLogger()
+			ctx = ContextWithProperties(NewContext(ctx, L), Properties{"a": 1})
+			e := L.WithContext(ctx)
+			Expect(e.Logger).To(BeIdenticalTo(L))
+			Expect(e.Properties).To(Equal(Properties{"a": 1}))
+			Expect(e.scope).To(BeEmpty())
+
+			e = WithContext(ctx)
+			Expect(e.Logger).To(BeIdenticalTo(L))
+			Expect(e.Properties).To(Equal(Properties{"a": 1}))
+		})
+		It("should create entry with scope of context", func() {
+			ctx = ContextWithScope(ctx)
+			Expect(WithContext(ctx).scope).To(Equal(ContextScope(ctx)))
+		})
+		It("should create entry in default logger", func() {
+			Expect(WithContext(ctx).Logger).To(BeIdenticalTo(defaultLogger))
+		})
+	})
+})
@@ -16,6 +16,10 @@
 
 package logger
 
+import (
+	"log"
+)
+
 // defaultLogger is the only global variable in logger package.
 // It contains the default logger.
 var defaultLogger = newDefaultLogger()
@@ -62,94 +66,173 @@ func RemoveAllBackends() {
 	defaultLogger.RemoveAllBackends()
 }
 
+// Stats returns statistics of all backends of default logger.
+func Stats() map[string]BackendStats {
+	return defaultLogger.Stats()
+}
+
+// AddEnricher adds enricher run on every entry logged by default logger.
+func AddEnricher(e Enricher) {
+	defaultLogger.AddEnricher(e)
+}
+
+// RemoveAllEnrichers removes all enrichers from default logger.
+func RemoveAllEnrichers() {
+	defaultLogger.RemoveAllEnrichers()
+}
+
+// SetErrorHandler sets handler of backends' failures in default logger.
+func SetErrorHandler(h ErrorHandler) {
+	defaultLogger.SetErrorHandler(h)
+}
+
+// SetParallel enables or disables parallel dispatching in default logger.
+func SetParallel(parallel bool) {
+	defaultLogger.SetParallel(parallel)
+}
+
+// SetStackThreshold makes default logger attach stack trace to entries with level equal
+// or less than given level.
+func SetStackThreshold(level Level) error {
+	return defaultLogger.SetStackThreshold(level)
+}
+
+// ResetStackThreshold stops attaching stack traces to entries by default logger's policy.
+func ResetStackThreshold() {
+	defaultLogger.ResetStackThreshold()
+}
+
+// Flush writes entries held by backends of default logger.
+func Flush() error {
+	return defaultLogger.Flush()
+}
+
+// Recover recovers from panic and logs it with stack trace at CritLevel to default logger.
+// Execution continues after the deferred call. It must be called directly by defer statement:
+//
+//	defer logger.Recover()
+func Recover() {
+	if value := recover(); value != nil {
+		defaultLogger.logPanic(value)
+	}
+}
+
+// RecoverRepanic recovers from panic, logs it to default logger like Recover does, flushes
+// backends and panics again with the same value. It must be called directly by defer statement.
+func RecoverRepanic() {
+	if value := recover(); value != nil {
+		defaultLogger.logPanic(value)
+		// Nothing can be done with failure of flushing while panicking.
+		_ = defaultLogger.Flush()
+		panic(value)
+	}
+}
+
+// RecoverExit recovers from panic, logs it to default logger like Recover does, flushes
+// backends and exits the process with given status code. It must be called directly by defer
+// statement.
+func RecoverExit(code int) {
+	if value := recover(); value != nil {
+		defaultLogger.logPanic(value)
+		// Nothing can be done with failure of flushing while exiting.
+		_ = defaultLogger.Flush()
+		exit(code)
+	}
+}
+
+// Go runs f in a new goroutine. Panic in f is recovered and logged to default logger.
+func Go(f func()) {
+	defaultLogger.Go(f)
+}
+
 // Log builds log message and logs entry to default logger.
 func Log(level Level, args ...interface{}) {
-	defaultLogger.IncDepth(1).Log(level, args...)
+	defaultLogger.log(level, args)
 }
 
 // Logf builds formatted log message and logs entry to default logger.
 func Logf(level Level, format string, args ...interface{}) {
-	defaultLogger.IncDepth(1).Logf(level, format, args...)
+	defaultLogger.logf(level, format, args)
 }
 
 // Emergency logs emergency level message to default logger.
 func Emergency(args ...interface{}) {
-	defaultLogger.IncDepth(1).Emergency(args...)
+	defaultLogger.log(EmergLevel, args)
 }
 
 // Alert logs alert level message to default logger.
 func Alert(args ...interface{}) {
-	defaultLogger.IncDepth(1).Alert(args...)
+	defaultLogger.log(AlertLevel, args)
 }
 
 // Critical logs critical level message to default logger.
 func Critical(args ...interface{}) {
-	defaultLogger.IncDepth(1).Critical(args...)
+	defaultLogger.log(CritLevel, args)
 }
 
 // Error logs error level message to default logger.
 func Error(args ...interface{}) {
-	defaultLogger.IncDepth(1).Error(args...)
+	defaultLogger.log(ErrLevel, args)
 }
 
 // Warning logs warning level message to default logger.
 func Warning(args ...interface{}) {
-	defaultLogger.IncDepth(1).Warning(args...)
+	defaultLogger.log(WarningLevel, args)
 }
 
 // Notice logs notice level message to default logger.
 func Notice(args ...interface{}) {
-	defaultLogger.IncDepth(1).Notice(args...)
+	defaultLogger.log(NoticeLevel, args)
 }
 
 // Info logs info level message to default logger.
 func Info(args ...interface{}) {
-	defaultLogger.IncDepth(1).Info(args...)
+	defaultLogger.log(InfoLevel, args)
 }
 
 // Debug logs debug level message to default logger.
 func Debug(args ...interface{}) {
-	defaultLogger.IncDepth(1).Debug(args...)
+	defaultLogger.log(DebugLevel, args)
 }
 
 // Emergencyf logs emergency level formatted message to default logger.
 func Emergencyf(format string, args ...interface{}) {
-	defaultLogger.IncDepth(1).Emergencyf(format, args...)
+	defaultLogger.logf(EmergLevel, format, args)
 }
 
 // Alertf logs alert level formatted message to default logger.
 func Alertf(format string, args ...interface{}) {
-	defaultLogger.IncDepth(1).Alertf(format, args...)
+	defaultLogger.logf(AlertLevel, format, args)
 }
 
 // Criticalf logs critical level formatted message to default logger.
 func Criticalf(format string, args ...interface{}) {
-	defaultLogger.IncDepth(1).Criticalf(format, args...)
+	defaultLogger.logf(CritLevel, format, args)
 }
 
 // Errorf logs error level formatted message to default logger.
 func Errorf(format string, args ...interface{}) {
-	defaultLogger.IncDepth(1).Errorf(format, args...)
+	defaultLogger.logf(ErrLevel, format, args)
 }
 
 // Warningf logs warning level formatted message to default logger.
 func Warningf(format string, args ...interface{}) {
-	defaultLogger.IncDepth(1).Warningf(format, args...)
+	defaultLogger.logf(WarningLevel, format, args)
 }
 
 // Noticef logs notice level formatted message to default logger.
 func Noticef(format string, args ...interface{}) {
-	defaultLogger.IncDepth(1).Noticef(format, args...)
+	defaultLogger.logf(NoticeLevel, format, args)
 }
 
 // Infof logs info level formatted message to default logger.
 func Infof(format string, args ...interface{}) {
-	defaultLogger.IncDepth(1).Infof(format, args...)
+	defaultLogger.logf(InfoLevel, format, args)
 }
 
 // Debugf logs debug level formatted message to default logger.
 func Debugf(format string, args ...interface{}) {
-	defaultLogger.IncDepth(1).Debugf(format, args...)
+	defaultLogger.logf(DebugLevel, format, args)
 }
 
 // WithProperty creates a log message with a single property in default logger.
@@ -162,12 +245,34 @@ func WithProperties(props Properties) *Entry {
 	return defaultLogger.WithProperties(props)
 }
 
+// WithFields creates a log message with typed fields in default logger.
+func WithFields(fields ...Field) *Entry {
+	return defaultLogger.WithFields(fields...)
+}
+
 // WithError creates a log message with an error property in default logger.
 func WithError(err error) *Entry {
 	return defaultLogger.WithError(err)
 }
 
+// WithStack creates a log message with stack trace attached in default logger.
+func WithStack() *Entry {
+	return defaultLogger.WithStack()
+}
+
 // IncDepth increases depth of an Entry for call stack calculations.
 func IncDepth(dep int) *Entry {
 	return defaultLogger.IncDepth(dep)
 }
+
+// StdLogger returns standard log package's Logger logging messages with given level
+// to default logger.
+func StdLogger(level Level) *log.Logger {
+	return defaultLogger.StdLogger(level)
+}
+
+// RedirectStdLog makes output of standard log package's functions logged with given level
+// by default logger. The returned function restores previous configuration of standard logger.
+func RedirectStdLog(level Level) (restore func()) {
+	return defaultLogger.RedirectStdLog(level)
+}
@@ -18,6 +18,7 @@ package logger
 
 import (
 	"errors"
+	"log"
 	"runtime"
 	"strconv"
 
@@ -42,9 +43,7 @@ var _ = Describe("Default", func() {
 		Expect(defaultLogger).NotTo(BeNil())
 		Expect(defaultLogger.mutex).NotTo(BeNil())
 		Expect(defaultLogger.threshold).To(Equal(DefaultThreshold))
-		defaultLogger.mutex.Lock()
-		defer defaultLogger.mutex.Unlock()
-		Expect(defaultLogger.backends).To(HaveKey("default"))
+		Expect(defaultLogger.load().backends).To(HaveKey("default"))
 	})
 	It("defaultLogger should log to stderr in default text format", func() {
 		stderr := withStderrMocked(func() {
@@ -157,12 +156,11 @@ var _ = Describe("Default", func() {
 		Describe("Backend", func() {
 			type Backends map[string]Backend
 			expectBackends := func(expected Backends) {
-				defaultLogger.mutex.Lock()
-				defer defaultLogger.mutex.Unlock()
-				Expect(defaultLogger.backends).To(HaveLen(len(expected)))
+				backends := defaultLogger.load().backends
+				Expect(backends).To(HaveLen(len(expected)))
 				for k, v := range expected {
 					v.Logger = defaultLogger
-					Expect(defaultLogger.backends).To(HaveKeyWithValue(k, v))
+					Expect(backends).To(HaveKeyWithValue(k, v))
 				}
 			}
 			BeforeEach(func() {
@@ -228,6 +226,61 @@ var _ = Describe("Default", func() {
 				})
 			})
 		})
+		Describe("Stats", func() {
+			It("should return statistics of default Logger's backends", func() {
+				AddBackend(backendName, mb)
+				Expect(Stats()).To(HaveKey(backendName))
+			})
+		})
+		Describe("SetErrorHandler", func() {
+			It("should set error handler of default Logger", func() {
+				h := NewErrorHandlerCount(nil)
+				SetErrorHandler(h)
+				Expect(L.load().errorHandler).To(BeIdenticalTo(h))
+			})
+		})
+		Describe("Flush", func() {
+			It("should flush backends of default Logger", func() {
+				w := new(flushCounter)
+				AddBackend(backendName, Backend{Filter: mf, Serializer: ms, Writer: w})
+				Expect(Flush()).To(Succeed())
+				Expect(w.flushed).To(Equal(1))
+			})
+		})
+		Describe("AddEnricher", func() {
+			It("should add and remove enrichers of default Logger", func() {
+				AddEnricher(EnricherFields{})
+				Expect(L.load().enrichers).To(HaveLen(1))
+				RemoveAllEnrichers()
+				Expect(L.load().enrichers).To(BeEmpty())
+			})
+		})
+		Describe("StdLogger", func() {
+			It("should create standard logger writing to default Logger", func() {
+				std := StdLogger(ErrLevel)
+				w, ok := std.Writer().(*LineWriter)
+				Expect(ok).To(BeTrue())
+				Expect(w.Logger).To(Equal(L))
+				Expect(w.Level).To(Equal(ErrLevel))
+			})
+		})
+		Describe("RedirectStdLog", func() {
+			It("should redirect standard logger to default Logger", func() {
+				restore := RedirectStdLog(ErrLevel)
+				w, ok := log.Writer().(*LineWriter)
+				restore()
+				Expect(ok).To(BeTrue())
+				Expect(w.Logger).To(Equal(L))
+			})
+		})
+		Describe("SetParallel", func() {
+			It("should set parallel dispatching of default Logger", func() {
+				SetParallel(true)
+				Expect(L.load().parallel).To(BeTrue())
+				SetParallel(false)
+				Expect(L.load().parallel).To(BeFalse())
+			})
+		})
 		Describe("Log functions", func() {
 			BeforeEach(func() {
 				AddBackend(backendName, mb)
@@ -265,7 +318,7 @@ var _ = Describe("Default", func() {
 						mf.EXPECT().Verify(gomock.Any()).
 							DoAndReturn(func(entry *Entry) (bool, error) {
 								// Quite a deep stack through reflect, gomock and filter_mock.
-								_, _, line, _ := runtime.Caller(13)
+								_, _, line, _ := runtime.Caller(12)
 								Expect(entry.Level).To(Equal(level))
 								Expect(entry.Message).To(Equal(testMessage + anotherTestMessage))
 								Expect(entry.CallContext.File).To(Equal(thisFile))
@@ -317,7 +370,7 @@ var _ = Describe("Default", func() {
 						mf.EXPECT().Verify(gomock.Any()).
 							DoAndReturn(func(entry *Entry) (bool, error) {
 								// Quite a deep stack through reflect, gomock and filter_mock.
-								_, _, line, _ := runtime.Caller(13)
+								_, _, line, _ := runtime.Caller(12)
 								Expect(entry.Level).To(Equal(level))
 								Expect(entry.Message).To(Equal(expectedMessage))
 								Expect(entry.CallContext.File).To(Equal(thisFile))
@@ -365,7 +418,29 @@ var _ = Describe("Default", func() {
 			It("should create a new log message with an error property", func() {
 				entry := WithError(errorValue)
 				Expect(entry.Properties).To(HaveLen(1))
-				Expect(entry.Properties).To(HaveKeyWithValue(ErrorProperty, errorValue.Error()))
+				Expect(entry.Properties).To(HaveKeyWithValue(ErrorProperty,
+					NewErrorValue(errorValue)))
+			})
+		})
+		Describe("WithFields", func() {
+			It("should create a new log message with fields", func() {
+				entry := WithFields(String("name", "Alice"))
+				Expect(entry.Fields).To(Equal([]Field{String("name", "Alice")}))
+			})
+		})
+		Describe("WithStack", func() {
+			It("should create a new log message with stack trace requested", func() {
+				entry := WithStack()
+				Expect(entry.withStack).To(BeTrue())
+			})
+		})
+		Describe("StackThreshold", func() {
+			It("should set and reset stack threshold of default logger", func() {
+				Expect(SetStackThreshold(ErrLevel)).To(Succeed())
+				Expect(L.load().stackEnabled).To(BeTrue())
+				Expect(L.load().stackThreshold).To(Equal(ErrLevel))
+				ResetStackThreshold()
+				Expect(L.load().stackEnabled).To(BeFalse())
 			})
 		})
 		Describe("IncDepth", func() {
@@ -99,6 +99,23 @@ There is also a special property for logging errors than can be added by WithErr
 	if err != nil {
 		logger.WithError(err).Error("Getting things done failed.")
 	}
+The value of error property is ErrorValue structure describing the error: its message, Go type,
+stack trace carried by the error, custom properties of errors implementing ErrorPropertiesProvider
+and all errors wrapped by it (also multiple errors joined with errors.Join). SerializerJSON writes
+the whole structure, while SerializerText writes only the error message.
+
+Typed fields are an alternative to properties. They are created with String, Int, Bool, Duration,
+Time, Err and Any functions and added with WithFields:
+	logger.WithFields(logger.String("user", name), logger.Duration("took", d)).Info("Logged in.")
+Values of basic types are stored without boxing and are encoded according to their types. Fields
+keep the order in which they were added and are serialized before properties. A field shadows
+a property with the same key.
+
+Values which are expensive to compute can be passed as functions returning them (func()
+interface{} or LazyValue), values implementing LogValuer interface or fields created with Lazy:
+	logger.WithProperty("queue", func() interface{} { return q.Dump() }).Debug("Queue state.")
+Such values are evaluated only if the entity passes threshold and filter of at least one backend,
+once for all backends. A panic during evaluation is logged as LazyPanicError in place of the value.
 
 Every log message entity gets CallContext during processing, containing:
 
@@ -117,6 +134,11 @@ Every log message entity gets CallContext during processing, containing:
 It is up to Serializer used in Backends (described further down this document) which information is
 written to logs.
 
+Call context is captured only if it is used by at least one of Logger's backends. Filters and
+Serializers declare it by implementing CallContextUser interface; the ones which do not implement
+it are assumed to use call context. Resolved call contexts are cached by program counter, so
+function names of repeated call sites are parsed only once.
+
 There are situations when some kind of auxiliary helper functions log an error. In such case your
 intention is probably to have context of calling the helper function rather than helper function
 itself. IncDepth method can be used to change the default call stack depth and get call context of
@@ -138,6 +160,24 @@ Will give you following (when default settings are used):
 	[0.000099] [ERR] [yourapp.go:25] "Failed to create object. IncDep(1)" {error:"error msg";}
 	[0.000209] [ERR] [yourapp.go:18] "Failed to create object." {error:"error msg";}
 
+Instead of counting stack depth, helper functions can mark themselves with Helper, just like
+testing.T.Helper does in tests. Marked functions are skipped when call context is captured, also
+when helpers are nested:
+	func exitOnErr(msg string, err error) {
+		logger.Helper()
+		if err != nil {
+			logger.WithError(err).Error(msg)
+			os.Exit(1)
+		}
+	}
+
+Full stack trace can be attached to log message entity with WithStack:
+	logger.WithStack().Error("Matcher failed.")
+Logger can also attach stack traces automatically to all entities with level equal or less than
+the one set with SetStackThreshold. Stack traces start at the call site, skip helper functions and
+omit frames of Go runtime. SerializerJSON writes them as an array of frames and SerializerText as
+an indented block following the log message.
+
 If methods from this paragraph are run on an existing Entry structure, they modify and return it.
 If they are run on Logger structure, they create and return new Entry structure with defined
 properties.
@@ -151,11 +191,91 @@ Processing of an entity consist of following steps:
 
 1) Verification of threshold. If it fails, the log entity is dropped.
 
-2) Adding timestamp and call context.
+2) Adding timestamp and call context and running enrichers.
 
 3) Passing an Entry structure to every Backend registered in Logger and continuing processing
 in every backend.
 
+Enrichers add information to entries, e.g. identity of the process. They are added to Logger with
+AddEnricher or set in Enrichers field of a Backend, so that only entries written by it are enriched
+(e.g. JSON shipped to a collector can carry identity, while console output stays terse):
+	log.AddEnricher(logger.EnricherFields{logger.String("env", "production")})
+	jsonBackend.Enrichers = []logger.Enricher{
+		logger.NewEnricherProcess(),   // hostname, PID and executable name
+		logger.NewEnricherBuild(),     // Go version, module version and VCS revision
+		logger.NewEnricherContainer(), // ID of container running the process
+	}
+
+Bridging other loggers
+
+Libraries writing to io.Writer or using standard log package can be bridged to Logger with
+LineWriter. It logs every written line as a separate entity with a chosen level or with level
+given by prefix of the line (e.g. "[ERROR]" or "warning:"):
+	client.SetOutput(logger.NewLineWriter(log, logger.InfoLevel))
+StdLogger returns log.Logger writing to LineWriter, while RedirectStdLog captures output of
+the global functions of standard log package:
+	restore := logger.RedirectStdLog(logger.InfoLevel)
+	defer restore()
+Call context of such entities points to the caller of standard log package's functions.
+
+Code using log/slog package can log with Logger through SlogHandler. Levels of records are mapped
+to Levels, attributes to properties (groups to nested Properties) and source to call context:
+	slog.SetDefault(slog.New(logger.NewSlogHandler(log)))
+In the other direction, Backend created with NewSlogBackend forwards entities to any slog.Handler,
+so both packages can share configured outputs.
+
+Packages logrusadapter, zapadapter and kitadapter log entries of logrus, zap and go-kit loggers
+with Logger. Their fields become properties and levels are mapped to Levels. Call context
+is the caller reported by the library or the first function outside of it:
+	logrusLogger.AddHook(logrusadapter.NewHook(log))
+	zapLogger := zap.New(zapadapter.NewCore(log))
+	kitLogger := kitadapter.NewLogger(log)
+
+Output of external commands can be logged with Command. Every line written by the command
+to stdout or stderr is logged as a separate entity with level of the stream and the final entity
+contains exit code and duration of the command:
+	err := log.Command(exec.Command("fota", "-map", mapping)).Run()
+
+Logging in HTTP and RPC services
+
+Context can carry Logger (NewContext) and properties (ContextWithProperties). WithContext creates
+entities with them, so all entities logged while handling a request can be correlated:
+	logger.WithContext(ctx).Info("job scheduled")
+HTTPHandler is a middleware logging every request with its method, path, status code, response
+size, duration, client address and request ID taken from X-Request-ID header or generated.
+Level depends on status class. Sampler can limit number of logged successful requests, while
+failed ones are always logged. Handlers get the request ID in context of the request:
+	http.ListenAndServe(addr, log.HTTPHandler(mux))
+HTTPTransport logs requests sent by HTTP clients with their status and duration. It propagates
+request ID from context of the request, can capture limited headers and bodies (with sensitive
+headers redacted) and retry failed idempotent requests logging every attempt. Bodies are captured
+while they are read, so such requests are logged when the response body is read up to the limit
+or closed:
+	client := &http.Client{Transport: log.HTTPTransport(http.DefaultTransport)}
+
+Entities of services taking part in handling the same job can be joined with W3C trace context
+(https://www.w3.org/TR/trace-context/). HTTPHandler takes TraceContext from traceparent
+and tracestate headers of the request (or starts a new trace) and puts it in context of the request.
+HTTPTransport propagates trace context carried by context of the outgoing request. Entities created
+with WithContext have trace_id and span_id properties, which SerializerJSON places in top level
+fields. Trace context can be also passed explicitly:
+	ctx = logger.ContextWithTrace(ctx, logger.NewTraceContext())
+
+Calls of net/rpc services are logged with RPCServerCodec and RPCClientCodec wrapping codecs
+of server and client. Every call is logged with its service method, sequence number, duration
+and error. Arguments of calls can be logged with DebugLevel (DumpArgs):
+	go server.ServeCodec(log.RPCServerCodec(jsonrpc.NewServerCodec(conn)))
+	client := rpc.NewClientWithCodec(log.RPCClientCodec(jsonrpc.NewClientCodec(conn)))
+
+Recovering from panics
+
+Panics can be logged with Recover functions, which must be called directly by defer statement:
+	defer logger.Recover()
+The panic value is logged at CritLevel with stack trace of the panicking goroutine. Recover
+continues execution after the deferred call, RecoverRepanic panics again and RecoverExit exits
+the process. Both of them flush backends holding entries (see Flusher interface) first. Go starts
+a goroutine with panics recovered and logged, so they do not crash the whole process.
+
 Backends
 
 Backends are customizable parts of logger that allow filtering logs, defining the way
@@ -181,6 +301,28 @@ Backends can be dynamically added or removed from Logger with following function
 After removing all backends, you should add at least one, as your logger won't be able to log
 anything at all.
 
+Logging does not block on modifications of backends. Logger keeps an immutable snapshot of its
+backends which is replaced on every modification, so concurrent logging calls never wait for each
+other nor for AddBackend. By default an Entry is passed to backends one after another. SetParallel
+makes Logger pass it to all backends concurrently, so a slow backend does not delay the others.
+As logging calls are not serialized, Filter, Serializer and Writer implementations must be safe
+for concurrent use. All implementations provided by this package are. Configuration of filters,
+serializers and writers should not be changed after they are registered in Logger.
+
+Failures of backends are passed to ErrorHandler set with SetErrorHandler. By default they are
+printed to standard error output (ErrorHandlerPrint). Other available handlers can count failures
+(ErrorHandlerCount), pass failed entities to another backend (ErrorHandlerFallback) or disable
+backends failing repeatedly for some time (ErrorHandlerBreaker).
+
+Logger collects statistics of every backend: number of accepted, filtered and skipped entities,
+serialized bytes, failures and a histogram of processing time. They can be read with Stats method
+or published as expvar variable with PublishStats method.
+
+Volume of logs can be monitored with Metrics. Its Backend counts entities by logger name, level
+and values of chosen properties. The backend writes nothing, so Stats report all of its entities
+as filtered. Metrics serves the counters over HTTP in Prometheus text exposition format and lists
+call sites producing the most entities (TopTalkers).
+
 Every backend consists of 3 elements:
 
 * Filter - for choosing which entities should be handled by the Backend;
@@ -189,14 +331,36 @@ Every backend consists of 3 elements:
 
 * Writer - for saving/sending entities.
 
+Backends can be wrapped to change the way a stream of entities is handled:
+
+* BackendDedup - collapses consecutive identical entities into a single "last message repeated
+N times" entity;
+
+* BackendFingersCrossed - buffers recent entities and writes them only when an entity with high
+enough level arrives, giving debug context of failures without writing all debug logs. History,
+which has not been triggered, is dropped when Logger is flushed.
+
 Filter
 
 Filter's role is to verify if log message entity should be logged by a backend.
 It is an interface that requires implementation of a single method:
 	Verify(*Entry) (bool, error)
 
-There is a FilterPassAll implementation of Filter implementation. It accepts all log message
-entities.
+There are 3 example implementations of this interface:
+
+* FilterPassAll - that accepts all log message entities;
+
+* FilterRateLimit - that limits number of entities using token bucket algorithm;
+
+* FilterSampling - that accepts only first entities in a time interval and every n-th after that
+or entities chosen with given probability.
+
+Limiting filters can group entities globally, by level, by call context, by value of a property
+or by scope of context (see ContextWithScope) and limit every group independently. They
+periodically log summary entities containing number of suppressed entities in every group,
+which are written only by the backend using the filter (or by all backends if the filter is used
+outside of backends, e.g. as HTTPHandler's Sampler). Pending summary is also logged when Logger
+is flushed. Up to MaxGroups groups are tracked, the least recently used ones are dropped.
 
 Serializer
 
@@ -215,6 +379,33 @@ or log files.
 Both of them are configurable. Please see fields' descriptions of structures defining them
 for details.
 
+SerializerRedact wraps another Serializer and hides secrets before serialization: values of
+properties and fields with keys matching patterns (e.g. "*token*"), fragments of messages, string
+values and error messages matching regular expressions and values implementing Redactor interface.
+Nested properties, maps and errors are redacted recursively. Secrets can be masked, replaced with
+a keyed hash (so they can still be correlated) or truncated. As it is a part of a Backend, every
+backend can redact different information:
+	log.AddBackend("shipped", Backend{
+		Filter:     NewFilterPassAll(),
+		Serializer: NewSerializerRedact(NewSerializerJSON(), "*token*", "password"),
+		Writer:     writer,
+	})
+
+Serializers can also implement AppendSerializer interface, which appends serialized entity
+to a buffer provided by the caller:
+	AppendSerialize(dst []byte, entry *Entry) ([]byte, error)
+Backends use it with pooled buffers, so no new buffer is allocated for every log message. Both
+SerializerJSON and SerializerText implement it and write entities directly into the buffer.
+SerializerJSON does not allocate memory at all for entities with common property values.
+
+Logging methods of Logger reuse pooled Entry structures. Calls with level not passing threshold
+do not allocate memory at all.
+
+Note that it changes the contract of Filter, Serializer and Enricher interfaces: implementations
+must not keep entities passed to them after returning. Implementations that need to keep them
+(e.g. buffering or asynchronous ones) must keep copies made with Entry's Clone method instead.
+ErrorHandlers get copies of entities, so they can keep them.
+
 Writer
 
 Writer's role is to save/send serialized log message entity.
@@ -0,0 +1,168 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+)
+
+const (
+	// HostnameProperty defines key of field holding name of the host.
+	HostnameProperty = "hostname"
+	// PIDProperty defines key of field holding process ID.
+	PIDProperty = "pid"
+	// ExecutableProperty defines key of field holding name of the executable.
+	ExecutableProperty = "executable"
+	// GoVersionProperty defines key of field holding version of Go used to build the executable.
+	GoVersionProperty = "go_version"
+	// ModuleProperty defines key of field holding path of the main module.
+	ModuleProperty = "module"
+	// ModuleVersionProperty defines key of field holding version of the main module.
+	ModuleVersionProperty = "module_version"
+	// VCSRevisionProperty defines key of field holding revision of the built sources.
+	VCSRevisionProperty = "vcs_revision"
+	// VCSTimeProperty defines key of field holding time of the built revision.
+	VCSTimeProperty = "vcs_time"
+	// VCSModifiedProperty defines key of field set to true if built sources were modified.
+	VCSModifiedProperty = "vcs_modified"
+	// ContainerIDProperty defines key of field holding ID of the container running the process.
+	ContainerIDProperty = "container_id"
+)
+
+// Enricher adds information to entries. Enrichers added to Logger with AddEnricher are run
+// on every entry passing threshold before it is passed to backends. Enrichers of a Backend
+// are run only on entries written by it.
+//
+// Entries passed to it must not be retained, see Entry.Clone.
+type Enricher interface {
+	// Enrich adds properties or fields to entry.
+	Enrich(entry *Entry)
+}
+
+// EnricherFunc is a function used as Enricher.
+type EnricherFunc func(entry *Entry)
+
+// Enrich calls f. It implements Enricher interface in EnricherFunc type.
+func (f EnricherFunc) Enrich(entry *Entry) {
+	f(entry)
+}
+
+// EnricherFields adds static fields (e.g. tags identifying environment) to entries.
+// Fields and properties already present in the entry are neither replaced nor shadowed.
+type EnricherFields []Field
+
+// Enrich adds fields to entry. It implements Enricher interface in EnricherFields type.
+func (f EnricherFields) Enrich(entry *Entry) {
+	for _, field := range f {
+		if _, ok := entry.Properties[field.Key]; ok {
+			continue
+		}
+		if entry.fieldIndex(field.Key) < 0 {
+			entry.WithFields(field)
+		}
+	}
+}
+
+// NewEnricherProcess creates Enricher adding hostname, process ID and name of the executable.
+func NewEnricherProcess() EnricherFields {
+	var f EnricherFields
+	if hostname, err := os.Hostname(); err == nil {
+		f = append(f, String(HostnameProperty, hostname))
+	}
+	f = append(f, Int(PIDProperty, os.Getpid()))
+	executable, err := os.Executable()
+	if err != nil {
+		executable = os.Args[0]
+	}
+	return append(f, String(ExecutableProperty, filepath.Base(executable)))
+}
+
+// NewEnricherBuild creates Enricher adding version of Go, path and version of the main module
+// and version control information embedded in the executable by Go toolchain.
+func NewEnricherBuild() EnricherFields {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return EnricherFields{String(GoVersionProperty, runtime.Version())}
+	}
+	return buildFields(info)
+}
+
+// buildFields returns fields describing build.
+func buildFields(info *debug.BuildInfo) EnricherFields {
+	f := EnricherFields{String(GoVersionProperty, runtime.Version())}
+	if info.GoVersion != "" {
+		f[0] = String(GoVersionProperty, info.GoVersion)
+	}
+	if info.Main.Path != "" {
+		f = append(f, String(ModuleProperty, info.Main.Path),
+			String(ModuleVersionProperty, info.Main.Version))
+	}
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			f = append(f, String(VCSRevisionProperty, s.Value))
+		case "vcs.time":
+			f = append(f, String(VCSTimeProperty, s.Value))
+		case "vcs.modified":
+			f = append(f, Bool(VCSModifiedProperty, s.Value == "true"))
+		}
+	}
+	return f
+}
+
+// containerIDSource is a file containing ID of container running the process.
+type containerIDSource struct {
+	// path is the path of the file.
+	path string
+	// re matches lines of the file containing container ID in one of its groups.
+	re *regexp.Regexp
+}
+
+// containerIDSources are searched for ID of container running the process. Only known forms
+// of paths are matched, as files of a process running outside of container can contain other
+// IDs, e.g. of overlay filesystem layers on Docker hosts.
+var containerIDSources = []containerIDSource{
+	// Docker on cgroup v1 and systemd scopes of Docker, containerd and CRI-O.
+	{"/proc/self/cgroup", regexp.MustCompile(
+		`(?m)(?:/docker/([0-9a-f]{64})|/(?:docker|cri-containerd|crio)-([0-9a-f]{64})\.scope)$`)},
+	// Bind mount of /etc/hostname in Docker container (also on cgroup v2 with private cgroup
+	// namespace, where cgroup file does not contain the ID).
+	{"/proc/self/mountinfo", regexp.MustCompile(`/containers/([0-9a-f]{64})/hostname `)},
+}
+
+// NewEnricherContainer creates Enricher adding ID of the container running the process.
+// It adds nothing if the process does not run in a container.
+func NewEnricherContainer() EnricherFields {
+	for _, src := range containerIDSources {
+		data, err := os.ReadFile(src.path)
+		if err != nil {
+			continue
+		}
+		match := src.re.FindSubmatch(data)
+		// The first element is the whole match, the ID is in one of the groups.
+		for i := 1; i < len(match); i++ {
+			if len(match[i]) > 0 {
+				return EnricherFields{String(ContainerIDProperty, string(match[i]))}
+			}
+		}
+	}
+	return nil
+}
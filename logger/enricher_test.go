@@ -0,0 +1,167 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	T "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Enricher", func() {
+	var e *Entry
+
+	BeforeEach(func() {
+		e = &Entry{}
+	})
+
+	Describe("EnricherFunc", func() {
+		It("should call itself", func() {
+			EnricherFunc(func(entry *Entry) {
+				entry.WithProperty("called", true)
+			}).Enrich(e)
+			Expect(e.Properties).To(HaveKeyWithValue("called", true))
+		})
+	})
+	Describe("EnricherFields", func() {
+		It("should add fields missing in entry", func() {
+			e.WithFields(String("env", "dev"))
+			EnricherFields{String("env", "prod"), String("dc", "eu")}.Enrich(e)
+			Expect(e.Fields).To(Equal([]Field{String("env", "dev"), String("dc", "eu")}))
+		})
+		It("should not shadow properties of entry", func() {
+			e.WithProperty("env", "dev")
+			EnricherFields{String("env", "prod"), String("dc", "eu")}.Enrich(e)
+			Expect(e.Fields).To(Equal([]Field{String("dc", "eu")}))
+			v, _ := e.property("env")
+			Expect(v).To(Equal("dev"))
+		})
+		It("should mark lazy values", func() {
+			EnricherFields{Lazy("lazy", func() interface{} { return 1 })}.Enrich(e)
+			Expect(e.lazy).NotTo(BeNil())
+		})
+	})
+	Describe("NewEnricherProcess", func() {
+		It("should add hostname, PID and executable", func() {
+			hostname, err := os.Hostname()
+			Expect(err).NotTo(HaveOccurred())
+			executable, err := os.Executable()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(NewEnricherProcess()).To(Equal(EnricherFields{
+				String(HostnameProperty, hostname),
+				Int(PIDProperty, os.Getpid()),
+				String(ExecutableProperty, filepath.Base(executable)),
+			}))
+		})
+	})
+	Describe("NewEnricherBuild", func() {
+		It("should add Go version", func() {
+			f := NewEnricherBuild()
+			Expect(f).NotTo(BeEmpty())
+			Expect(f[0].Key).To(Equal(GoVersionProperty))
+			Expect(f[0].String).To(HavePrefix("go"))
+		})
+		It("should add module and version control information", func() {
+			info := &debug.BuildInfo{
+				Main: debug.Module{Path: "example.com/app", Version: "v1.2.3"},
+				Settings: []debug.BuildSetting{
+					{Key: "-compiler", Value: "gc"},
+					{Key: "vcs.revision", Value: "abcdef"},
+					{Key: "vcs.time", Value: "2018-07-09T10:11:12Z"},
+					{Key: "vcs.modified", Value: "true"},
+				},
+			}
+			Expect(buildFields(info)).To(Equal(EnricherFields{
+				String(GoVersionProperty, runtime.Version()),
+				String(ModuleProperty, "example.com/app"),
+				String(ModuleVersionProperty, "v1.2.3"),
+				String(VCSRevisionProperty, "abcdef"),
+				String(VCSTimeProperty, "2018-07-09T10:11:12Z"),
+				Bool(VCSModifiedProperty, true),
+			}))
+			info.GoVersion = "go1.42"
+			Expect(buildFields(info)[0]).To(Equal(String(GoVersionProperty, "go1.42")))
+		})
+	})
+	Describe("NewEnricherContainer", func() {
+		var (
+			tmp     string
+			sources []containerIDSource
+		)
+		id := strings.Repeat("0123456789abcdef", 4)
+		layer := strings.Repeat("fedcba9876543210", 4)
+		hostMountinfo := "120 29 0:50 / /var/lib/docker/overlay2/" + layer + "/merged rw " +
+			"- overlay overlay rw,upperdir=/var/lib/docker/overlay2/" + layer + "/diff\n" +
+			"130 29 0:51 / /var/lib/docker/containers/" + id + "/mounts/shm rw - tmpfs shm rw\n"
+
+		BeforeEach(func() {
+			var err error
+			tmp, err = os.MkdirTemp("", "enricher")
+			Expect(err).NotTo(HaveOccurred())
+			sources = containerIDSources
+			containerIDSources = []containerIDSource{
+				{filepath.Join(tmp, "missing"), sources[0].re},
+				{filepath.Join(tmp, "cgroup"), sources[0].re},
+				{filepath.Join(tmp, "mountinfo"), sources[1].re},
+			}
+		})
+		AfterEach(func() {
+			containerIDSources = sources
+			Expect(os.RemoveAll(tmp)).To(Succeed())
+		})
+
+		write := func(cgroup, mountinfo string) {
+			Expect(os.WriteFile(containerIDSources[1].path, []byte(cgroup), 0600)).
+				To(Succeed())
+			Expect(os.WriteFile(containerIDSources[2].path, []byte(mountinfo), 0600)).
+				To(Succeed())
+		}
+
+		T.DescribeTable("should add container ID found in cgroup files",
+			func(cgroup, mountinfo string) {
+				write(cgroup, mountinfo)
+				Expect(NewEnricherContainer()).To(Equal(EnricherFields{
+					String(ContainerIDProperty, id),
+				}))
+			},
+			T.Entry("docker on cgroup v1", "12:memory:/docker/"+id+"\n1:cpu:/\n", ""),
+			T.Entry("docker systemd scope",
+				"0::/system.slice/docker-"+id+".scope\n", ""),
+			T.Entry("containerd systemd scope",
+				"0::/kubepods.slice/cri-containerd-"+id+".scope\n", ""),
+			T.Entry("docker on cgroup v2", "0::/\n",
+				"120 29 0:50 / / rw - overlay overlay rw,upperdir=/var/lib/docker/overlay2/"+
+					layer+"/diff\n"+
+					"131 120 8:1 /var/lib/docker/containers/"+id+"/hostname /etc/hostname "+
+					"rw - ext4 /dev/sda1 rw\n"),
+		)
+		It("should add nothing outside of container", func() {
+			write("0::/\n", "")
+			Expect(NewEnricherContainer()).To(BeEmpty())
+		})
+		It("should add nothing on Docker host", func() {
+			write("0::/user.slice/user-1000.slice/session-1.scope\n", hostMountinfo)
+			Expect(NewEnricherContainer()).To(BeEmpty())
+		})
+	})
+})
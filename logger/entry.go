@@ -31,12 +31,60 @@ type Entry struct {
 	Message string
 	// Properties hold key-value pairs of log message properties.
 	Properties Properties
+	// Fields hold typed properties of log message in order in which they were added.
+	Fields []Field
 	// Timestamp stores point in time of log message creation.
 	Timestamp time.Time
 	// CallContext stores the source code context of log creation.
 	CallContext *CallContext
+	// Stack stores the stack trace of log creation. It is captured only if requested
+	// with WithStack or by Logger's stack threshold.
+	Stack []CallContext
 	// depth is a call depth of a stack frame of a caller
 	depth int
+	// withStack set to true requests capturing of the stack trace.
+	withStack bool
+	// lazy is set if properties or fields contain lazy values.
+	lazy *lazyState
+	// pc is program counter of the call site set with WithCaller.
+	// If set, it is used instead of capturing call context.
+	pc uintptr
+	// noCaller set to true omits call context, when there is no meaningful call site.
+	noCaller bool
+	// scope is the scope carried by context of the entry (see ContextWithScope).
+	scope string
+	// summaryOf is the limiting filter, which suppressed entries summarized by the entry.
+	// Summaries are never limited and are passed only to the backend using the filter, if any.
+	summaryOf Filter
+}
+
+// clone returns a copy of an Entry which does not share properties, fields, call context
+// and stack trace with the original.
+func (e *Entry) clone() *Entry {
+	c := *e
+	c.Properties = nil
+	if len(e.Properties) > 0 {
+		c.WithProperties(e.Properties)
+	}
+	if e.Fields != nil {
+		c.Fields = append([]Field(nil), e.Fields...)
+	}
+	if e.CallContext != nil {
+		ctx := *e.CallContext
+		c.CallContext = &ctx
+	}
+	if e.Stack != nil {
+		c.Stack = append([]CallContext(nil), e.Stack...)
+	}
+	return &c
+}
+
+// Clone returns a copy of an Entry which does not share properties, fields, call context
+// and stack trace with the original. Logging methods of Logger reuse entries after they are
+// processed, so Filters, Serializers and Enrichers must not retain entries passed to them
+// after their methods return. They can retain copies made with Clone instead.
+func (e *Entry) Clone() *Entry {
+	return e.clone()
 }
 
 // IncDepth increases depth of an Entry for call stack frame calculation.
@@ -45,18 +93,37 @@ func (e *Entry) IncDepth(dep int) *Entry {
 	return e
 }
 
+// sprint formats args like fmt.Sprint does, but returns a single string argument as is.
+func sprint(args []interface{}) string {
+	if len(args) == 1 {
+		if s, ok := args[0].(string); ok {
+			return s
+		}
+	}
+	return fmt.Sprint(args...)
+}
+
 // Log builds log message and logs entry.
+// The message is not built if level does not pass threshold.
 func (e *Entry) Log(level Level, args ...interface{}) {
-	e.IncDepth(1).process(level, fmt.Sprint(args...))
+	if !e.Logger.PassThreshold(level) {
+		return
+	}
+	e.IncDepth(1).process(level, sprint(args))
 }
 
 // Logf builds formatted log message and logs entry.
+// The message is not built if level does not pass threshold.
 func (e *Entry) Logf(level Level, format string, args ...interface{}) {
+	if !e.Logger.PassThreshold(level) {
+		return
+	}
 	e.IncDepth(1).process(level, fmt.Sprintf(format, args...))
 }
 
 // process verifies if log level is above threshold and logs entry.
-// It acquires timestamp, and source code context.
+// It acquires timestamp, source code context if any of backends uses it
+// and stack trace if requested. Then it runs Logger's enrichers.
 func (e *Entry) process(level Level, msg string) {
 	if !e.Logger.PassThreshold(level) {
 		return
@@ -64,11 +131,36 @@ func (e *Entry) process(level Level, msg string) {
 	e.Level = level
 	e.Message = msg
 	e.Timestamp = time.Now()
-	e.CallContext = getCallContext(e.depth + 1)
+	d := e.Logger.load()
+	e.CallContext = nil
+	if d.usesCallContext() {
+		e.CallContext = e.callContext()
+	}
+	e.Stack = nil
+	if e.withStack || d.passStackThreshold(level) {
+		e.Stack = getStack(e.depth + 1)
+	}
+	d.enrich(e)
+	if e.lazy != nil {
+		e.lazy = new(lazyState)
+	}
 
 	e.Logger.process(e)
 }
 
+// callContext returns call context of the call site given by pc or of the caller
+// of process method.
+func (e *Entry) callContext() *CallContext {
+	if e.noCaller {
+		return nil
+	}
+	if e.pc != 0 {
+		ctx := resolveCallSite(e.pc).ctx
+		return &ctx
+	}
+	return getCallContext(e.depth + 2)
+}
+
 // Emergency logs emergency level message.
 func (e *Entry) Emergency(args ...interface{}) {
 	e.IncDepth(1).Log(EmergLevel, args...)
@@ -161,11 +253,115 @@ func (e *Entry) WithProperties(props Properties) *Entry {
 	}
 	for k, v := range props {
 		e.Properties[k] = v
+		if e.lazy == nil && isLazy(v) {
+			e.lazy = new(lazyState)
+		}
 	}
 	return e
 }
 
-// WithError adds error property to the log message.
+// WithFields adds typed fields to the log message. A field replaces the previously added field
+// with the same key, keeping its position; other fields are appended in the given order.
+func (e *Entry) WithFields(fields ...Field) *Entry {
+	for _, f := range fields {
+		if e.lazy == nil && f.Type == FieldTypeAny && isLazy(f.Interface) {
+			e.lazy = new(lazyState)
+		}
+		if i := e.fieldIndex(f.Key); i >= 0 {
+			e.Fields[i] = f
+			continue
+		}
+		e.Fields = append(e.Fields, f)
+	}
+	return e
+}
+
+// fieldIndex returns index of the field with given key or -1 if there is no such field.
+func (e *Entry) fieldIndex(key string) int {
+	for i := range e.Fields {
+		if e.Fields[i].Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// property returns value of the field or property with given key. Fields take precedence over
+// properties with the same key.
+func (e *Entry) property(key string) (interface{}, bool) {
+	if i := e.fieldIndex(key); i >= 0 {
+		return e.Fields[i].Value(), true
+	}
+	v, ok := e.Properties[key]
+	return v, ok
+}
+
+// resolved returns entry with lazy values of properties and fields evaluated. Values are
+// evaluated only once for entry and all its clones; the entry itself is not modified.
+func (e *Entry) resolved() *Entry {
+	l := e.lazy
+	if l == nil {
+		return e
+	}
+	l.once.Do(func() {
+		l.entry = e.clone()
+		l.entry.lazy = nil
+		l.entry.evaluate()
+	})
+	return l.entry
+}
+
+// evaluate replaces lazy values of properties and fields with their values.
+// Panics are logged as ErrorValue of LazyPanicError.
+func (e *Entry) evaluate() {
+	for k, v := range e.Properties {
+		if !isLazy(v) {
+			continue
+		}
+		value, err := evaluate(v)
+		if err != nil {
+			value = NewErrorValue(err)
+		}
+		e.Properties[k] = value
+	}
+	for i, f := range e.Fields {
+		if f.Type != FieldTypeAny || !isLazy(f.Interface) {
+			continue
+		}
+		value, err := evaluate(f.Interface)
+		if err != nil {
+			value = err
+		}
+		e.Fields[i] = Any(f.Key, value)
+	}
+}
+
+// WithCaller sets program counter of the call site used as call context of the log message
+// instead of capturing it. It is intended for adapters of other logging libraries, which provide
+// their own caller information. The pc is a return address as returned by runtime.Callers
+// (for runtime.Frame it is Frame.PC+1). Zero pc restores capturing.
+func (e *Entry) WithCaller(pc uintptr) *Entry {
+	e.pc = pc
+	return e
+}
+
+// withoutCaller omits call context of the log message.
+func (e *Entry) withoutCaller() *Entry {
+	e.noCaller = true
+	return e
+}
+
+// WithStack requests attaching stack trace to the log message.
+func (e *Entry) WithStack() *Entry {
+	e.withStack = true
+	return e
+}
+
+// WithError adds error property to the log message. The value of the property is ErrorValue
+// describing the error and its unwrap chain. Nil error is logged as nil value.
 func (e *Entry) WithError(err error) *Entry {
-	return e.WithProperty(ErrorProperty, err.Error())
+	if err == nil {
+		return e.WithProperty(ErrorProperty, nil)
+	}
+	return e.WithProperty(ErrorProperty, NewErrorValue(err))
 }
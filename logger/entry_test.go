@@ -101,6 +101,17 @@ var _ = Describe("Entry", func() {
 			entry.IncDepth(100000)
 			entry.process(WarningLevel, testMessage)
 		})
+		It("should not set CallContext if no backend uses it", func() {
+			L.AddBackend(backendName, Backend{
+				Filter:     NewFilterPassAll(),
+				Serializer: newPlainSerializerText(),
+				Writer:     mw,
+			})
+			mw.EXPECT().Write(WarningLevel, gomock.Any())
+			entry.CallContext = &CallContext{}
+			entry.process(WarningLevel, testMessage)
+			Expect(entry.CallContext).To(BeNil())
+		})
 		It("should not set anything and return if level doesn't pass threshold", func() {
 			L.SetThreshold(ErrLevel)
 			entry.process(WarningLevel, testMessage)
@@ -153,6 +164,26 @@ var _ = Describe("Entry", func() {
 			T.Entry("InfoLevel", InfoLevel, (*Entry).Info),
 			T.Entry("DebugLevel", DebugLevel, (*Entry).Debug),
 		)
+		It("should not build message if level does not pass threshold", func() {
+			L.SetThreshold(InfoLevel)
+			built := false
+			entry.Log(DebugLevel, stringerFunc(func() string {
+				built = true
+				return testMessage
+			}))
+			Expect(built).To(BeFalse())
+			Expect(entry.Message).To(BeEmpty())
+		})
+	})
+	Describe("sprint", func() {
+		It("should return single string argument as is", func() {
+			Expect(sprint([]interface{}{testMessage})).To(Equal(testMessage))
+		})
+		It("should format arguments like fmt.Sprint", func() {
+			args := []interface{}{testMessage, 1, 2, anotherTestMessage}
+			Expect(sprint(args)).To(Equal(fmt.Sprint(args...)))
+			Expect(sprint(nil)).To(BeEmpty())
+		})
 	})
 	Describe("Logf", func() {
 		T.DescribeTable("should properly build log message and pass to logger's backend",
@@ -267,7 +298,7 @@ var _ = Describe("Entry", func() {
 			e := entry.WithError(errorValue)
 			Expect(e).To(Equal(entry))
 			Expect(entry.Properties).To(HaveLen(1))
-			Expect(entry.Properties).To(HaveKeyWithValue(ErrorProperty, errorValue.Error()))
+			Expect(entry.Properties).To(HaveKeyWithValue(ErrorProperty, NewErrorValue(errorValue)))
 		})
 		It("should overwrite an error property", func() {
 			Expect(entry.Properties).To(BeEmpty())
@@ -276,7 +307,14 @@ var _ = Describe("Entry", func() {
 			e := entry.WithError(anotherErrorValue)
 			Expect(e).To(Equal(entry))
 			Expect(entry.Properties).To(HaveLen(1))
-			Expect(entry.Properties).To(HaveKeyWithValue(ErrorProperty, anotherErrorValue.Error()))
+			Expect(entry.Properties).To(HaveKeyWithValue(ErrorProperty,
+				NewErrorValue(anotherErrorValue)))
+		})
+		It("should add nil error property for nil error", func() {
+			Expect(func() {
+				entry.WithError(nil)
+			}).NotTo(Panic())
+			Expect(entry.Properties).To(HaveKeyWithValue(ErrorProperty, BeNil()))
 		})
 		It("should create properties map if nil", func() {
 			Expect(entry.Properties).NotTo(BeNil())
@@ -294,6 +332,72 @@ var _ = Describe("Entry", func() {
 			Expect(entry.Properties).To(HaveKeyWithValue(anotherProperty, anotherValue))
 		})
 	})
+	Describe("WithFields", func() {
+		It("should append fields in order", func() {
+			e := entry.WithFields(String("b", "x"), Int("a", 1))
+			Expect(e).To(Equal(entry))
+			e.WithFields(Bool("c", true))
+			Expect(entry.Fields).To(Equal([]Field{String("b", "x"), Int("a", 1),
+				Bool("c", true)}))
+		})
+		It("should replace field with the same key in place", func() {
+			entry.WithFields(String("b", "x"), Int("a", 1))
+			entry.WithFields(String("b", "y"))
+			Expect(entry.Fields).To(Equal([]Field{String("b", "y"), Int("a", 1)}))
+		})
+	})
+	Describe("property", func() {
+		It("should return value of field or property", func() {
+			entry.Properties = Properties{"a": "property", "b": "property"}
+			entry.WithFields(Int("a", 1))
+			v, ok := entry.property("a")
+			Expect(ok).To(BeTrue())
+			Expect(v).To(Equal(1))
+			v, ok = entry.property("b")
+			Expect(ok).To(BeTrue())
+			Expect(v).To(Equal("property"))
+			_, ok = entry.property("c")
+			Expect(ok).To(BeFalse())
+		})
+	})
+	Describe("WithCaller", func() {
+		It("should use call site of given program counter as call context", func() {
+			var pcs [1]uintptr
+			runtime.Callers(1, pcs[:])
+			_, _, line, _ := runtime.Caller(0)
+			e := entry.WithCaller(pcs[0])
+			Expect(e).To(Equal(entry))
+			mf.EXPECT().Verify(entry).DoAndReturn(func(entry *Entry) (bool, error) {
+				Expect(entry.CallContext.File).To(Equal(thisFile))
+				Expect(entry.CallContext.Line).To(Equal(line - 1))
+				return false, nil
+			})
+			entry.process(WarningLevel, testMessage)
+		})
+	})
+	Describe("WithStack", func() {
+		It("should request stack trace", func() {
+			e := entry.WithStack()
+			Expect(e).To(Equal(entry))
+			Expect(entry.withStack).To(BeTrue())
+		})
+		It("should attach stack trace when processed", func() {
+			mf.EXPECT().Verify(entry).DoAndReturn(func(entry *Entry) (bool, error) {
+				Expect(entry.Stack).NotTo(BeEmpty())
+				Expect(entry.Stack[0].File).To(Equal(thisFile))
+				Expect(*entry.CallContext).To(Equal(entry.Stack[0]))
+				return false, nil
+			})
+			entry.WithStack().process(WarningLevel, testMessage)
+		})
+		It("should not attach stack trace if not requested", func() {
+			mf.EXPECT().Verify(entry).DoAndReturn(func(entry *Entry) (bool, error) {
+				Expect(entry.Stack).To(BeNil())
+				return false, nil
+			})
+			entry.process(WarningLevel, testMessage)
+		})
+	})
 	Describe("IncDepth", func() {
 		const dep = 67
 		It("should increase depth of entry call stack", func() {
@@ -307,4 +411,31 @@ var _ = Describe("Entry", func() {
 			}
 		})
 	})
+	Describe("clone", func() {
+		It("should copy entry without sharing properties and call context", func() {
+			entry.Level = NoticeLevel
+			entry.Message = testMessage
+			entry.WithProperty("key", "value")
+			entry.CallContext = &CallContext{File: "file.go", Line: 7}
+			entry.Stack = []CallContext{{File: "file.go", Line: 7}}
+			entry.WithFields(Int("field", 7))
+
+			c := entry.clone()
+			Expect(c).To(Equal(entry))
+			c.Properties["key"] = "another value"
+			c.CallContext.Line = 8
+			c.Stack[0].Line = 8
+			c.Fields[0] = Int("field", 8)
+			Expect(entry.Properties).To(HaveKeyWithValue("key", "value"))
+			Expect(entry.Fields).To(Equal([]Field{Int("field", 7)}))
+			Expect(entry.CallContext.Line).To(Equal(7))
+			Expect(entry.Stack[0].Line).To(Equal(7))
+		})
+		It("should handle entry without properties and call context", func() {
+			entry.Properties = nil
+			c := entry.clone()
+			Expect(c.Properties).To(BeNil())
+			Expect(c.CallContext).To(BeNil())
+		})
+	})
 })
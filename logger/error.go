@@ -27,4 +27,11 @@ var (
 
 	// ErrInvalidEntry is returned in case of invalid entry struct.
 	ErrInvalidEntry = errors.New("invalid log entry structure")
+
+	// ErrInvalidTraceparent is returned in case of malformed W3C traceparent header.
+	ErrInvalidTraceparent = errors.New("invalid traceparent")
+
+	// ErrInvalidMetricsLabel is returned in case of property name, which cannot be used
+	// as a label of Metrics.
+	ErrInvalidMetricsLabel = errors.New("invalid metrics label")
 )
@@ -0,0 +1,288 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// ErrorHandler handles failures of Logger's backends.
+type ErrorHandler interface {
+	// HandleError is called when backend with given name fails to process entry.
+	// The entry is a copy, which can be retained by the handler.
+	HandleError(backend string, entry *Entry, err error)
+}
+
+// BackendGate is an optional interface of ErrorHandler that allows temporary disabling
+// of failing backends. Logger does not pass entries to backends which are not allowed.
+// Logger uses the first BackendGate found in the chain of handlers, so e.g. ErrorHandlerBreaker
+// wrapped in ErrorHandlerCount still gates backends. Only handlers of this package are walked:
+// a BackendGate wrapped in a custom ErrorHandler is not found.
+type BackendGate interface {
+	// Allow decides if entry should be passed to backend with given name.
+	Allow(backend string) bool
+	// Succeeded is called when backend with given name processes entry successfully.
+	Succeeded(backend string)
+}
+
+// errorHandlerChain is implemented by handlers passing errors to the next handler.
+type errorHandlerChain interface {
+	// nextErrorHandler returns the next handler or nil.
+	nextErrorHandler() ErrorHandler
+}
+
+// findBackendGate returns the first BackendGate in the chain of handlers starting with h
+// or nil if there is none.
+func findBackendGate(h ErrorHandler) BackendGate {
+	for h != nil {
+		if gate, ok := h.(BackendGate); ok {
+			return gate
+		}
+		chain, ok := h.(errorHandlerChain)
+		if !ok {
+			return nil
+		}
+		h = chain.nextErrorHandler()
+	}
+	return nil
+}
+
+// ErrorHandlerPrint prints information about backends' failures.
+// It is the default ErrorHandler of every Logger.
+// It implements ErrorHandler interface.
+type ErrorHandlerPrint struct {
+	// Output defines where information is printed. If nil, os.Stderr is used.
+	Output io.Writer
+}
+
+// NewErrorHandlerPrint creates and returns a new ErrorHandlerPrint object printing
+// to standard error output.
+func NewErrorHandlerPrint() *ErrorHandlerPrint {
+	return &ErrorHandlerPrint{}
+}
+
+// HandleError prints the error. It implements ErrorHandler interface in ErrorHandlerPrint.
+func (h *ErrorHandlerPrint) HandleError(backend string, _ *Entry, err error) {
+	out := h.Output
+	if out == nil {
+		out = os.Stderr
+	}
+	// Potential fail of printing is ignored.
+	_, _ = fmt.Fprintf(out, "Error <%s> printing log message to <%s> backend.\n",
+		err.Error(), backend)
+}
+
+// ErrorHandlerCount counts failures of every backend and passes them to the next handler.
+// It implements ErrorHandler interface.
+type ErrorHandlerCount struct {
+	// next handles errors after counting. It can be nil.
+	next ErrorHandler
+	// errors contains number of failures of every backend.
+	errors map[string]uint64
+	// mutex protects errors from concurrent access.
+	mutex *sync.Mutex
+}
+
+// NewErrorHandlerCount creates and returns a new ErrorHandlerCount object passing errors
+// to next handler. Next handler can be nil.
+func NewErrorHandlerCount(next ErrorHandler) *ErrorHandlerCount {
+	return &ErrorHandlerCount{
+		next:   next,
+		errors: make(map[string]uint64),
+		mutex:  new(sync.Mutex),
+	}
+}
+
+// HandleError counts the error. It implements ErrorHandler interface in ErrorHandlerCount.
+func (h *ErrorHandlerCount) HandleError(backend string, entry *Entry, err error) {
+	h.mutex.Lock()
+	h.errors[backend]++
+	h.mutex.Unlock()
+	if h.next != nil {
+		h.next.HandleError(backend, entry, err)
+	}
+}
+
+// nextErrorHandler returns the next handler.
+// It implements errorHandlerChain interface in ErrorHandlerCount.
+func (h *ErrorHandlerCount) nextErrorHandler() ErrorHandler {
+	return h.next
+}
+
+// Errors returns number of failures of every backend.
+func (h *ErrorHandlerCount) Errors() map[string]uint64 {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	ret := make(map[string]uint64, len(h.errors))
+	for k, v := range h.errors {
+		ret[k] = v
+	}
+	return ret
+}
+
+// ErrorHandlerFallback passes entries that failed to be processed to a fallback backend.
+// If fallback backend fails as well, error of fallback backend wrapped together with
+// the original error is passed to the next handler.
+// It implements ErrorHandler interface.
+type ErrorHandlerFallback struct {
+	// fallback is the backend processing failed entries.
+	fallback Backend
+	// next handles errors of fallback backend. It can be nil.
+	next ErrorHandler
+}
+
+// NewErrorHandlerFallback creates and returns a new ErrorHandlerFallback object passing failed
+// entries to fallback backend and its errors to next handler. Next handler can be nil.
+func NewErrorHandlerFallback(fallback Backend, next ErrorHandler) *ErrorHandlerFallback {
+	return &ErrorHandlerFallback{
+		fallback: fallback,
+		next:     next,
+	}
+}
+
+// HandleError passes entry to fallback backend.
+// It implements ErrorHandler interface in ErrorHandlerFallback.
+func (h *ErrorHandlerFallback) HandleError(backend string, entry *Entry, err error) {
+	ferr := h.fallback.process(entry)
+	if ferr != nil && h.next != nil {
+		h.next.HandleError(backend, entry, fmt.Errorf("%w; fallback: %w", err, ferr))
+	}
+}
+
+// nextErrorHandler returns the next handler.
+// It implements errorHandlerChain interface in ErrorHandlerFallback.
+func (h *ErrorHandlerFallback) nextErrorHandler() ErrorHandler {
+	return h.next
+}
+
+// breakerState holds state of a single backend in ErrorHandlerBreaker.
+type breakerState struct {
+	// failures is the number of consecutive failures.
+	failures uint64
+	// retry is the time when disabled backend is tried again.
+	retry time.Time
+}
+
+// ErrorHandlerBreaker disables backends after Threshold consecutive failures (circuit breaker).
+// Disabled backend is tried again with a single entry after RetryInterval. If it succeeds,
+// backend is enabled again, otherwise it stays disabled for another RetryInterval.
+// Errors are passed to the next handler.
+// It implements ErrorHandler and BackendGate interfaces.
+type ErrorHandlerBreaker struct {
+	// Threshold defines number of consecutive failures disabling a backend.
+	Threshold uint64
+	// RetryInterval defines time after which disabled backend is tried again.
+	RetryInterval time.Duration
+
+	// next handles errors. It can be nil.
+	next ErrorHandler
+	// states contains states of failing backends.
+	states map[string]*breakerState
+	// mutex protects states from concurrent access.
+	mutex *sync.Mutex
+	// now returns current time.
+	now func() time.Time
+}
+
+// NewErrorHandlerBreaker creates and returns a new ErrorHandlerBreaker object disabling
+// backends after threshold consecutive failures for retry interval and passing errors to next
+// handler. Next handler can be nil.
+func NewErrorHandlerBreaker(threshold uint64, retry time.Duration,
+	next ErrorHandler) *ErrorHandlerBreaker {
+
+	return &ErrorHandlerBreaker{
+		Threshold:     threshold,
+		RetryInterval: retry,
+		next:          next,
+		states:        make(map[string]*breakerState),
+		mutex:         new(sync.Mutex),
+		now:           time.Now,
+	}
+}
+
+// HandleError counts consecutive failures and disables backend if Threshold is reached.
+// It implements ErrorHandler interface in ErrorHandlerBreaker.
+func (h *ErrorHandlerBreaker) HandleError(backend string, entry *Entry, err error) {
+	h.mutex.Lock()
+	s, ok := h.states[backend]
+	if !ok {
+		s = new(breakerState)
+		h.states[backend] = s
+	}
+	s.failures++
+	if s.failures >= h.Threshold {
+		s.retry = h.now().Add(h.RetryInterval)
+	}
+	h.mutex.Unlock()
+	if h.next != nil {
+		h.next.HandleError(backend, entry, err)
+	}
+}
+
+// nextErrorHandler returns the next handler.
+// It implements errorHandlerChain interface in ErrorHandlerBreaker.
+func (h *ErrorHandlerBreaker) nextErrorHandler() ErrorHandler {
+	return h.next
+}
+
+// Allow returns false if backend is disabled and it is not the time to retry yet.
+// It implements BackendGate interface in ErrorHandlerBreaker.
+func (h *ErrorHandlerBreaker) Allow(backend string) bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	s, ok := h.states[backend]
+	if !ok || s.failures < h.Threshold {
+		return true
+	}
+	if h.now().Before(s.retry) {
+		return false
+	}
+	// Let a single entry through and postpone other retries.
+	s.retry = h.now().Add(h.RetryInterval)
+	return true
+}
+
+// Succeeded enables backend and resets its failures counter.
+// It implements BackendGate interface in ErrorHandlerBreaker.
+func (h *ErrorHandlerBreaker) Succeeded(backend string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	delete(h.states, backend)
+}
+
+// Disabled verifies if backend with given name is disabled.
+func (h *ErrorHandlerBreaker) Disabled(backend string) bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	s, ok := h.states[backend]
+	return ok && s.failures >= h.Threshold
+}
+
+// Failures returns number of consecutive failures of backend with given name.
+func (h *ErrorHandlerBreaker) Failures(backend string) uint64 {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	s, ok := h.states[backend]
+	if !ok {
+		return 0
+	}
+	return s.failures
+}
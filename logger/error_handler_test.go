@@ -0,0 +1,242 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"time"
+
+	gomock "github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// errorHandlerCollector is an ErrorHandler collecting names of failed backends, entries
+// and errors.
+type errorHandlerCollector struct {
+	backends []string
+	entries  []*Entry
+	errors   []error
+}
+
+func (h *errorHandlerCollector) HandleError(backend string, entry *Entry, err error) {
+	h.backends = append(h.backends, backend)
+	h.entries = append(h.entries, entry)
+	h.errors = append(h.errors, err)
+}
+
+var _ = Describe("ErrorHandler", func() {
+	const (
+		backendName        = string("backendName")
+		anotherBackendName = string("anotherBackendName")
+	)
+	var (
+		ctrl      *gomock.Controller
+		mf        *MockFilter
+		ms        *MockSerializer
+		mw        *MockWriter
+		mb        Backend
+		entry     *Entry
+		testError error
+		next      *errorHandlerCollector
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mf = NewMockFilter(ctrl)
+		ms = NewMockSerializer(ctrl)
+		mw = NewMockWriter(ctrl)
+		mb = Backend{
+			Filter:     mf,
+			Serializer: ms,
+			Writer:     mw,
+		}
+		entry = &Entry{
+			Level:   ErrLevel,
+			Message: "Message",
+		}
+		testError = errors.New("Test Error")
+		next = new(errorHandlerCollector)
+	})
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should get copies of entries, which can be retained", func() {
+		L := NewLogger()
+		L.SetErrorHandler(next)
+		L.AddBackend(backendName, Backend{
+			Filter: filterFunc(func(*Entry) (bool, error) {
+				return false, testError
+			}),
+		})
+		L.Error("retained")
+		L.Error("another")
+		Expect(next.entries).To(HaveLen(2))
+		Expect(next.entries[0].Message).To(Equal("retained"))
+		Expect(next.entries[0].Logger).To(BeIdenticalTo(L))
+	})
+	Describe("ErrorHandlerPrint", func() {
+		It("should print to stderr by default", func() {
+			h := NewErrorHandlerPrint()
+			stderr := withStderrMocked(func() {
+				h.HandleError(backendName, entry, testError)
+			})
+			Expect(stderr).To(Equal("Error <Test Error> printing log message to" +
+				" <backendName> backend.\n"))
+		})
+		It("should print to given output", func() {
+			buf := new(bytes.Buffer)
+			h := &ErrorHandlerPrint{Output: buf}
+			h.HandleError(backendName, entry, testError)
+			Expect(buf.String()).To(Equal("Error <Test Error> printing log message to" +
+				" <backendName> backend.\n"))
+		})
+	})
+	Describe("ErrorHandlerCount", func() {
+		It("should count errors of every backend", func() {
+			h := NewErrorHandlerCount(next)
+			Expect(h.Errors()).To(BeEmpty())
+			h.HandleError(backendName, entry, testError)
+			h.HandleError(backendName, entry, testError)
+			h.HandleError(anotherBackendName, entry, testError)
+			Expect(h.Errors()).To(Equal(map[string]uint64{
+				backendName:        2,
+				anotherBackendName: 1,
+			}))
+			Expect(next.backends).To(HaveLen(3))
+		})
+		It("should work without next handler", func() {
+			h := NewErrorHandlerCount(nil)
+			h.HandleError(backendName, entry, testError)
+			Expect(h.Errors()).To(HaveKeyWithValue(backendName, uint64(1)))
+		})
+	})
+	Describe("ErrorHandlerFallback", func() {
+		It("should pass entry to fallback backend", func() {
+			h := NewErrorHandlerFallback(mb, next)
+			gomock.InOrder(
+				mf.EXPECT().Verify(entry).Return(true, nil),
+				ms.EXPECT().Serialize(entry).Return([]byte("Lorem ipsum"), nil),
+				mw.EXPECT().Write(entry.Level, []byte("Lorem ipsum")),
+			)
+			h.HandleError(backendName, entry, testError)
+			Expect(next.backends).To(BeEmpty())
+		})
+		It("should pass error to next handler if fallback fails", func() {
+			h := NewErrorHandlerFallback(mb, next)
+			fallbackError := errors.New("Fallback Error")
+			mf.EXPECT().Verify(entry).Return(false, fallbackError)
+			h.HandleError(backendName, entry, testError)
+			Expect(next.backends).To(Equal([]string{backendName}))
+			Expect(next.errors[0]).To(MatchError("Test Error; fallback: Fallback Error"))
+			Expect(errors.Is(next.errors[0], testError)).To(BeTrue())
+			Expect(errors.Is(next.errors[0], fallbackError)).To(BeTrue())
+		})
+	})
+	Describe("ErrorHandlerBreaker", func() {
+		var (
+			h   *ErrorHandlerBreaker
+			now time.Time
+		)
+		BeforeEach(func() {
+			h = NewErrorHandlerBreaker(2, time.Minute, next)
+			now = time.Unix(1234567890, 0)
+			h.now = func() time.Time { return now }
+		})
+		It("should disable backend after threshold consecutive failures", func() {
+			Expect(h.Allow(backendName)).To(BeTrue())
+			h.HandleError(backendName, entry, testError)
+			Expect(h.Failures(backendName)).To(Equal(uint64(1)))
+			Expect(h.Disabled(backendName)).To(BeFalse())
+			Expect(h.Allow(backendName)).To(BeTrue())
+
+			h.HandleError(backendName, entry, testError)
+			Expect(h.Disabled(backendName)).To(BeTrue())
+			Expect(h.Allow(backendName)).To(BeFalse())
+			Expect(h.Allow(anotherBackendName)).To(BeTrue())
+			Expect(next.backends).To(HaveLen(2))
+		})
+		It("should reset failures on success", func() {
+			h.HandleError(backendName, entry, testError)
+			h.Succeeded(backendName)
+			Expect(h.Failures(backendName)).To(BeZero())
+			h.HandleError(backendName, entry, testError)
+			Expect(h.Disabled(backendName)).To(BeFalse())
+		})
+		It("should retry a single entry after retry interval", func() {
+			h.HandleError(backendName, entry, testError)
+			h.HandleError(backendName, entry, testError)
+			now = now.Add(time.Minute)
+			Expect(h.Allow(backendName)).To(BeTrue())
+			Expect(h.Allow(backendName)).To(BeFalse())
+
+			h.HandleError(backendName, entry, testError)
+			now = now.Add(time.Second)
+			Expect(h.Allow(backendName)).To(BeFalse())
+
+			now = now.Add(time.Minute)
+			Expect(h.Allow(backendName)).To(BeTrue())
+			h.Succeeded(backendName)
+			Expect(h.Disabled(backendName)).To(BeFalse())
+			Expect(h.Allow(backendName)).To(BeTrue())
+		})
+		It("should skip disabled backends in Logger", func() {
+			L := NewLogger()
+			L.SetErrorHandler(h)
+			L.AddBackend(backendName, mb)
+
+			mf.EXPECT().Verify(entry).Return(false, testError).Times(2)
+			for i := 0; i < 5; i++ {
+				L.process(entry)
+			}
+			Expect(h.Disabled(backendName)).To(BeTrue())
+
+			now = now.Add(time.Minute)
+			mf.EXPECT().Verify(entry).Return(false, nil)
+			L.process(entry)
+			Expect(h.Disabled(backendName)).To(BeFalse())
+		})
+		It("should skip disabled backends if wrapped in other handlers", func() {
+			L := NewLogger()
+			c := NewErrorHandlerCount(h)
+			L.SetErrorHandler(c)
+			L.AddBackend(backendName, mb)
+
+			mf.EXPECT().Verify(entry).Return(false, testError).Times(2)
+			for i := 0; i < 5; i++ {
+				L.process(entry)
+			}
+			Expect(h.Disabled(backendName)).To(BeTrue())
+			Expect(c.Errors()).To(HaveKeyWithValue(backendName, uint64(2)))
+		})
+	})
+	Describe("findBackendGate", func() {
+		It("should find gate in chain of handlers", func() {
+			h := NewErrorHandlerBreaker(1, time.Minute, nil)
+			Expect(findBackendGate(h)).To(BeIdenticalTo(h))
+			Expect(findBackendGate(NewErrorHandlerCount(NewErrorHandlerFallback(mb, h)))).
+				To(BeIdenticalTo(h))
+		})
+		It("should return nil if there is no gate in chain", func() {
+			Expect(findBackendGate(NewErrorHandlerCount(next))).To(BeNil())
+			Expect(findBackendGate(NewErrorHandlerCount(nil))).To(BeNil())
+			Expect(findBackendGate(nil)).To(BeNil())
+		})
+	})
+})
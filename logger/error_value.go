@@ -0,0 +1,127 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// maxErrorDepth limits depth of errors unwrapped by NewErrorValue.
+const maxErrorDepth = 32
+
+// ErrorPropertiesProvider is implemented by errors which provide custom properties to be logged
+// along with them.
+type ErrorPropertiesProvider interface {
+	// ErrorProperties returns properties describing the error.
+	ErrorProperties() Properties
+}
+
+// ErrorValue is a structured value of error property added to log message by WithError
+// functions. It describes the error and all errors wrapped by it.
+type ErrorValue struct {
+	// Message is the result of Error method of the error.
+	Message string `json:"message"`
+	// Type is the Go type of the error.
+	Type string `json:"type"`
+	// Properties contains custom fields of errors implementing ErrorPropertiesProvider.
+	Properties Properties `json:"properties,omitempty"`
+	// Stack contains stack trace carried by the error. Errors created with
+	// github.com/pkg/errors and all others having StackTrace method returning a slice
+	// of program counters are supported.
+	Stack []CallContext `json:"stack,omitempty"`
+	// Causes contains values of errors wrapped by the error: a single one if it implements
+	// Unwrap() error method or many if it implements Unwrap() []error (e.g. errors.Join).
+	Causes []*ErrorValue `json:"causes,omitempty"`
+}
+
+// NewErrorValue creates structured value describing given error and its unwrap chain.
+// It returns nil for nil error.
+func NewErrorValue(err error) *ErrorValue {
+	return newErrorValue(err, maxErrorDepth)
+}
+
+// newErrorValue creates ErrorValue unwrapping err up to given depth.
+func newErrorValue(err error, depth int) *ErrorValue {
+	if err == nil {
+		return nil
+	}
+	v := &ErrorValue{
+		// fmt handles panics of Error method, e.g. when called on nil pointer.
+		Message: fmt.Sprint(err),
+		Type:    fmt.Sprintf("%T", err),
+	}
+	if r := reflect.ValueOf(err); r.Kind() == reflect.Ptr && r.IsNil() {
+		// Other methods can not be safely called on nil pointer.
+		return v
+	}
+	v.Stack = errorStack(err)
+	if p, ok := err.(ErrorPropertiesProvider); ok {
+		v.Properties = p.ErrorProperties()
+	}
+	if depth <= 1 {
+		return v
+	}
+	for _, cause := range unwrapError(err) {
+		if c := newErrorValue(cause, depth-1); c != nil {
+			v.Causes = append(v.Causes, c)
+		}
+	}
+	return v
+}
+
+// unwrapError returns errors wrapped by err.
+func unwrapError(err error) []error {
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		if cause := u.Unwrap(); cause != nil {
+			return []error{cause}
+		}
+	case interface{ Unwrap() []error }:
+		return u.Unwrap()
+	}
+	return nil
+}
+
+// errorStack returns stack trace carried by err. Error's StackTrace method is called with
+// reflection, as stack trace types (like github.com/pkg/errors.StackTrace) are slices
+// of named types based on uintptr.
+func errorStack(err error) []CallContext {
+	m := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+		return nil
+	}
+	out := m.Type().Out(0)
+	if out.Kind() != reflect.Slice || out.Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+	pcs := m.Call(nil)[0]
+	stack := make([]CallContext, 0, pcs.Len())
+	for i := 0; i < pcs.Len(); i++ {
+		stack = append(stack, resolveCallSite(uintptr(pcs.Index(i).Uint())).ctx)
+	}
+	return stack
+}
+
+// String returns message of the error. It implements fmt.Stringer interface in ErrorValue,
+// so text serializers write error message only.
+func (v *ErrorValue) String() string {
+	if v == nil {
+		return fmt.Sprint(nil)
+	}
+	return v.Message
+}
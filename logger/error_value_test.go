@@ -0,0 +1,155 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// propertiesError is an error providing custom properties.
+type propertiesError struct {
+	dryad string
+}
+
+func (e *propertiesError) Error() string {
+	return "dryad " + e.dryad + " failed"
+}
+
+func (e *propertiesError) ErrorProperties() Properties {
+	return Properties{"dryad": e.dryad}
+}
+
+// stackFrame mimics github.com/pkg/errors.Frame type.
+type stackFrame uintptr
+
+// stackError is an error carrying stack trace like errors of github.com/pkg/errors.
+type stackError struct {
+	pcs []stackFrame
+}
+
+func newStackError() *stackError {
+	pcs := make([]uintptr, 8)
+	n := runtime.Callers(2, pcs)
+	e := &stackError{}
+	for _, pc := range pcs[:n] {
+		e.pcs = append(e.pcs, stackFrame(pc))
+	}
+	return e
+}
+
+func (e *stackError) Error() string {
+	return "stack"
+}
+
+func (e *stackError) StackTrace() []stackFrame {
+	return e.pcs
+}
+
+// loopError is an error wrapping itself.
+type loopError struct{}
+
+func (e *loopError) Error() string {
+	return "loop"
+}
+
+func (e *loopError) Unwrap() error {
+	return e
+}
+
+var _ = Describe("ErrorValue", func() {
+	Describe("NewErrorValue", func() {
+		It("should return nil for nil error", func() {
+			Expect(NewErrorValue(nil)).To(BeNil())
+		})
+		It("should describe simple error", func() {
+			Expect(NewErrorValue(errors.New("simple"))).To(Equal(&ErrorValue{
+				Message: "simple",
+				Type:    "*errors.errorString",
+			}))
+		})
+		It("should describe unwrap chain", func() {
+			base := errors.New("base")
+			err := fmt.Errorf("outer: %w", fmt.Errorf("inner: %w", base))
+			Expect(NewErrorValue(err)).To(Equal(&ErrorValue{
+				Message: "outer: inner: base",
+				Type:    "*fmt.wrapError",
+				Causes: []*ErrorValue{{
+					Message: "inner: base",
+					Type:    "*fmt.wrapError",
+					Causes: []*ErrorValue{{
+						Message: "base",
+						Type:    "*errors.errorString",
+					}},
+				}},
+			}))
+		})
+		It("should describe all errors joined with errors.Join", func() {
+			err := errors.Join(errors.New("first"), &propertiesError{dryad: "rpi3"})
+			v := NewErrorValue(err)
+			Expect(v.Type).To(Equal("*errors.joinError"))
+			Expect(v.Causes).To(Equal([]*ErrorValue{
+				{Message: "first", Type: "*errors.errorString"},
+				{
+					Message:    "dryad rpi3 failed",
+					Type:       "*logger.propertiesError",
+					Properties: Properties{"dryad": "rpi3"},
+				},
+			}))
+		})
+		It("should handle nil pointer error", func() {
+			var err *propertiesError
+			v := NewErrorValue(err)
+			Expect(v.Type).To(Equal("*logger.propertiesError"))
+			Expect(v.Message).To(Equal("<nil>"))
+			Expect(v.Properties).To(BeNil())
+		})
+		It("should attach stack trace carried by error", func() {
+			_, _, line, _ := runtime.Caller(0)
+			v := NewErrorValue(newStackError())
+			Expect(v.Stack).NotTo(BeEmpty())
+			Expect(v.Stack[0].File).To(Equal("error_value_test.go"))
+			Expect(v.Stack[0].Line).To(Equal(line + 1))
+		})
+		It("should limit depth of unwrapping", func() {
+			v := NewErrorValue(&loopError{})
+			depth := 0
+			for ; v != nil; depth++ {
+				if len(v.Causes) == 0 {
+					v = nil
+					continue
+				}
+				v = v.Causes[0]
+			}
+			Expect(depth).To(Equal(maxErrorDepth))
+		})
+	})
+	Describe("String", func() {
+		It("should return error message", func() {
+			Expect(fmt.Sprint(NewErrorValue(fmt.Errorf("outer: %w", errors.New("base"))))).
+				To(Equal("outer: base"))
+		})
+		It("should handle nil value", func() {
+			var v *ErrorValue
+			Expect(v.String()).To(Equal("<nil>"))
+		})
+	})
+})
@@ -0,0 +1,256 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// FieldType defines type of value stored in Field.
+type FieldType uint8
+
+const (
+	// FieldTypeAny - value of any type stored in Interface.
+	FieldTypeAny FieldType = iota
+	// FieldTypeString - string value stored in String.
+	FieldTypeString
+	// FieldTypeInt - integer value stored in Integer.
+	FieldTypeInt
+	// FieldTypeBool - boolean value stored in Integer (1 for true).
+	FieldTypeBool
+	// FieldTypeDuration - time.Duration value stored in Integer.
+	FieldTypeDuration
+	// FieldTypeTime - time.Time value stored in Interface. Unix time in nanoseconds cannot
+	// represent times outside years 1678-2262, so the value is boxed.
+	FieldTypeTime
+	// FieldTypeError - error value stored in Interface.
+	FieldTypeError
+)
+
+// fieldTimeFormat is the format of time values of fields.
+const fieldTimeFormat = time.RFC3339Nano
+
+// Field is a typed key-value property of log message. Unlike Properties, values of basic types
+// are stored without boxing them in interface{} and fields keep order in which they were added.
+// Fields are created with String, Int, Bool, Duration, Time, Err and Any functions.
+type Field struct {
+	// Key is the name of the field.
+	Key string
+	// Type defines which member holds the value.
+	Type FieldType
+	// Integer holds value of FieldTypeInt, FieldTypeBool and FieldTypeDuration.
+	Integer int64
+	// String holds value of FieldTypeString.
+	String string
+	// Interface holds value of FieldTypeAny, FieldTypeError and FieldTypeTime.
+	Interface interface{}
+}
+
+// String creates a field with string value.
+func String(key, value string) Field {
+	return Field{Key: key, Type: FieldTypeString, String: value}
+}
+
+// Int creates a field with integer value.
+func Int(key string, value int) Field {
+	return Field{Key: key, Type: FieldTypeInt, Integer: int64(value)}
+}
+
+// Bool creates a field with boolean value.
+func Bool(key string, value bool) Field {
+	f := Field{Key: key, Type: FieldTypeBool}
+	if value {
+		f.Integer = 1
+	}
+	return f
+}
+
+// Duration creates a field with time.Duration value.
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, Type: FieldTypeDuration, Integer: int64(value)}
+}
+
+// Time creates a field with time.Time value. Monotonic clock reading is stripped.
+func Time(key string, value time.Time) Field {
+	return Field{Key: key, Type: FieldTypeTime, Interface: value.Round(0)}
+}
+
+// Err creates a field with error value under ErrorProperty key.
+func Err(err error) Field {
+	return Field{Key: ErrorProperty, Type: FieldTypeError, Interface: err}
+}
+
+// Any creates a field with value of any type. Values of types supported by other field
+// constructors are stored as typed fields.
+func Any(key string, value interface{}) Field {
+	switch v := value.(type) {
+	case string:
+		return String(key, v)
+	case int:
+		return Int(key, v)
+	case bool:
+		return Bool(key, v)
+	case time.Duration:
+		return Duration(key, v)
+	case time.Time:
+		return Time(key, v)
+	case error:
+		f := Err(v)
+		f.Key = key
+		return f
+	}
+	return Field{Key: key, Type: FieldTypeAny, Interface: value}
+}
+
+// time returns value of FieldTypeTime field.
+func (f Field) time() time.Time {
+	t, _ := f.Interface.(time.Time)
+	return t
+}
+
+// Value returns value of the field boxed in interface{}. Error values are returned
+// as ErrorValue, like in properties added by WithError.
+func (f Field) Value() interface{} {
+	switch f.Type {
+	case FieldTypeString:
+		return f.String
+	case FieldTypeInt:
+		return int(f.Integer)
+	case FieldTypeBool:
+		return f.Integer != 0
+	case FieldTypeDuration:
+		return time.Duration(f.Integer)
+	case FieldTypeTime:
+		return f.time()
+	case FieldTypeError:
+		if err, ok := f.Interface.(error); ok {
+			return NewErrorValue(err)
+		}
+		return nil
+	}
+	return f.Interface
+}
+
+// text returns value of the field formatted for humans.
+func (f Field) text() string {
+	switch f.Type {
+	case FieldTypeString:
+		return f.String
+	case FieldTypeInt:
+		return strconv.FormatInt(f.Integer, 10)
+	case FieldTypeBool:
+		return strconv.FormatBool(f.Integer != 0)
+	case FieldTypeDuration:
+		return time.Duration(f.Integer).String()
+	case FieldTypeTime:
+		return f.time().Format(fieldTimeFormat)
+	}
+	return fmt.Sprint(f.Value())
+}
+
+// appendJSON appends value of the field encoded to JSON to buf.
+func (f Field) appendJSON(buf []byte) ([]byte, error) {
+	switch f.Type {
+	case FieldTypeString:
+		return appendJSONString(buf, f.String), nil
+	case FieldTypeInt:
+		return strconv.AppendInt(buf, f.Integer, 10), nil
+	case FieldTypeBool:
+		return strconv.AppendBool(buf, f.Integer != 0), nil
+	case FieldTypeDuration, FieldTypeTime:
+		return appendJSONString(buf, f.text()), nil
+	}
+	data, err := json.Marshal(f.Value())
+	if err != nil {
+		return buf, err
+	}
+	return append(buf, data...), nil
+}
+
+// hexDigits are used for escaping characters in JSON strings.
+const hexDigits = "0123456789abcdef"
+
+// jsonSafe defines ASCII characters which do not need escaping in JSON strings.
+var jsonSafe = func() (safe [utf8.RuneSelf]bool) {
+	for b := ' '; b < utf8.RuneSelf; b++ {
+		safe[b] = !strings.ContainsRune(`"\<>&`, b)
+	}
+	return safe
+}()
+
+// appendJSONString appends s encoded as JSON string to buf. Characters are escaped the same way
+// as encoding/json does it.
+func appendJSONString(buf []byte, s string) []byte {
+	buf = append(buf, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if b := s[i]; b < utf8.RuneSelf {
+			if !jsonSafe[b] {
+				buf = appendJSONEscapedByte(append(buf, s[start:i]...), b)
+				start = i + 1
+			}
+			i++
+			continue
+		}
+		c, size := utf8.DecodeRuneInString(s[i:])
+		if escaped := jsonEscapedRune(c, size); escaped != "" {
+			buf = append(append(buf, s[start:i]...), escaped...)
+			start = i + size
+		}
+		i += size
+	}
+	buf = append(buf, s[start:]...)
+	return append(buf, '"')
+}
+
+// appendJSONEscapedByte appends escaped ASCII character to buf.
+func appendJSONEscapedByte(buf []byte, b byte) []byte {
+	switch b {
+	case '"', '\\':
+		return append(buf, '\\', b)
+	case '\b':
+		return append(buf, '\\', 'b')
+	case '\f':
+		return append(buf, '\\', 'f')
+	case '\n':
+		return append(buf, '\\', 'n')
+	case '\r':
+		return append(buf, '\\', 'r')
+	case '\t':
+		return append(buf, '\\', 't')
+	}
+	return append(buf, '\\', 'u', '0', '0', hexDigits[b>>4], hexDigits[b&0xF])
+}
+
+// jsonEscapedRune returns replacement of non-ASCII rune of given encoded size in JSON string
+// or empty string if the rune does not need to be replaced.
+func jsonEscapedRune(c rune, size int) string {
+	switch {
+	case c == utf8.RuneError && size == 1:
+		return "\ufffd"
+	case c == '\u2028':
+		return `\u2028`
+	case c == '\u2029':
+		return `\u2029`
+	}
+	return ""
+}
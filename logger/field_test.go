@@ -0,0 +1,101 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	T "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Field", func() {
+	location := time.FixedZone("CET", 3600)
+	timestamp := time.Date(2018, 7, 9, 10, 11, 12, 13, location)
+	testError := errors.New("test error")
+
+	T.DescribeTable("Any should create typed fields",
+		func(value interface{}, typ FieldType) {
+			f := Any("key", value)
+			Expect(f.Key).To(Equal("key"))
+			Expect(f.Type).To(Equal(typ))
+		},
+		T.Entry("string", "value", FieldTypeString),
+		T.Entry("int", 7, FieldTypeInt),
+		T.Entry("bool", true, FieldTypeBool),
+		T.Entry("duration", time.Second, FieldTypeDuration),
+		T.Entry("time", timestamp, FieldTypeTime),
+		T.Entry("error", testError, FieldTypeError),
+		T.Entry("other", 3.5, FieldTypeAny),
+		T.Entry("nil", nil, FieldTypeAny),
+	)
+	T.DescribeTable("should return value, text and JSON",
+		func(f Field, value interface{}, text, encoded string) {
+			if value == nil {
+				Expect(f.Value()).To(BeNil())
+			} else {
+				Expect(f.Value()).To(Equal(value))
+			}
+			Expect(f.text()).To(Equal(text))
+			buf, err := f.appendJSON([]byte("prefix "))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(buf)).To(Equal("prefix " + encoded))
+		},
+		T.Entry("string", String("k", "v"), "v", "v", `"v"`),
+		T.Entry("int", Int("k", -12), -12, "-12", `-12`),
+		T.Entry("true", Bool("k", true), true, "true", `true`),
+		T.Entry("false", Bool("k", false), false, "false", `false`),
+		T.Entry("duration", Duration("k", 90*time.Second), 90*time.Second, "1m30s", `"1m30s"`),
+		T.Entry("time", Time("k", timestamp), timestamp, "2018-07-09T10:11:12.000000013+01:00",
+			`"2018-07-09T10:11:12.000000013+01:00"`),
+		T.Entry("zero time", Time("k", time.Time{}), time.Time{}, "0001-01-01T00:00:00Z",
+			`"0001-01-01T00:00:00Z"`),
+		T.Entry("far future time", Time("k", time.Date(3000, 1, 2, 3, 4, 5, 6, time.UTC)),
+			time.Date(3000, 1, 2, 3, 4, 5, 6, time.UTC), "3000-01-02T03:04:05.000000006Z",
+			`"3000-01-02T03:04:05.000000006Z"`),
+		T.Entry("error", Err(testError), NewErrorValue(testError), "test error",
+			`{"message":"test error","type":"*errors.errorString"}`),
+		T.Entry("nil error", Err(nil), nil, "<nil>", `null`),
+		T.Entry("any", Any("k", []int{1, 2}), []int{1, 2}, "[1 2]", `[1,2]`),
+	)
+	It("should create error field with error property key", func() {
+		Expect(Err(testError).Key).To(Equal(ErrorProperty))
+		Expect(Any("cause", testError).Key).To(Equal("cause"))
+	})
+	It("should return error if value cannot be encoded", func() {
+		buf, err := Any("k", make(chan int)).appendJSON([]byte("prefix"))
+		Expect(err).To(HaveOccurred())
+		Expect(buf).To(Equal([]byte("prefix")))
+	})
+	T.DescribeTable("appendJSONString should escape like encoding/json",
+		func(s string) {
+			expected, err := json.Marshal(s)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(appendJSONString(nil, s)).To(Equal(expected))
+		},
+		T.Entry("plain", "Lorem ipsum"),
+		T.Entry("quotes and backslashes", `"a\b"`),
+		T.Entry("control characters", "\b\f\n\r\t\x00\x1f"),
+		T.Entry("HTML", "<a href='x'>&</a>"),
+		T.Entry("unicode", "zażółć gęślą jaźń ☺"),
+		T.Entry("invalid UTF-8", "a\xffb\xc3"),
+		T.Entry("line separators", "a\u2028b\u2029c"),
+	)
+})
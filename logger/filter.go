@@ -17,9 +17,33 @@
 package logger
 
 // Filter verifies if entry should be logged by a backend.
+//
+// Entries passed to it must not be retained, see Entry.Clone.
 type Filter interface {
 	// Verify decides if log entry should be processed by a backend.
 	// It returns true if entry should be processed.
 	// It returns false if entry should be ignored.
 	Verify(*Entry) (bool, error)
 }
+
+// filterWrapper is implemented by Filters passing entries to a wrapped Filter
+// (e.g. BackendDedup), so that the backend using the wrapped Filter can be found.
+type filterWrapper interface {
+	// wrappedFilter returns the wrapped Filter.
+	wrappedFilter() Filter
+}
+
+// usesFilter returns true if f is filter or wraps it.
+func usesFilter(f, filter Filter) bool {
+	for f != nil {
+		if f == filter {
+			return true
+		}
+		w, ok := f.(filterWrapper)
+		if !ok {
+			return false
+		}
+		f = w.wrappedFilter()
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"container/list"
+)
+
+const (
+	// DefaultFilterMaxGroups is the default number of groups tracked by limiting filters
+	// at the same time.
+	DefaultFilterMaxGroups = 10000
+)
+
+// filterGroup is a state of a single group of entries of a limiting filter.
+type filterGroup struct {
+	// key identifies the group.
+	key string
+	// state is the filter-specific state of the group.
+	state interface{}
+}
+
+// filterGroups holds states of groups of entries of a limiting filter from the most
+// to the least recently used. It must be protected from concurrent access by the filter.
+type filterGroups struct {
+	// elems maps keys of groups to elements of order list.
+	elems map[string]*list.Element
+	// order contains filterGroup objects from the most to the least recently used.
+	order *list.List
+}
+
+// newFilterGroups creates and returns a new empty filterGroups object.
+func newFilterGroups() *filterGroups {
+	return &filterGroups{
+		elems: make(map[string]*list.Element),
+		order: list.New(),
+	}
+}
+
+// get returns state of the group identified by key and marks the group as the most
+// recently used.
+func (g *filterGroups) get(key string) (interface{}, bool) {
+	el, ok := g.elems[key]
+	if !ok {
+		return nil, false
+	}
+	g.order.MoveToFront(el)
+	return el.Value.(*filterGroup).state, true
+}
+
+// add stores state of a new group identified by key as the most recently used. If there are
+// more than max groups, the least recently used ones are dropped. Non-positive max means
+// no limit.
+func (g *filterGroups) add(key string, state interface{}, max int) {
+	g.elems[key] = g.order.PushFront(&filterGroup{key: key, state: state})
+	for max > 0 && g.order.Len() > max {
+		el := g.order.Back()
+		delete(g.elems, el.Value.(*filterGroup).key)
+		g.order.Remove(el)
+	}
+}
+
+// len returns number of held groups.
+func (g *filterGroups) len() int {
+	return g.order.Len()
+}
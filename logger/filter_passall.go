@@ -29,3 +29,9 @@ func NewFilterPassAll() *FilterPassAll {
 func (*FilterPassAll) Verify(*Entry) (bool, error) {
 	return true, nil
 }
+
+// UsesCallContext returns false as FilterPassAll does not use call context.
+// It implements CallContextUser interface in FilterPassAll type.
+func (*FilterPassAll) UsesCallContext() bool {
+	return false
+}
@@ -45,4 +45,9 @@ var _ = Describe("FilterPassAll", func() {
 			Expect(ret).To(BeTrue())
 		})
 	})
+	Describe("UsesCallContext", func() {
+		It("should return false", func() {
+			Expect(f.UsesCallContext()).To(BeFalse())
+		})
+	})
 })
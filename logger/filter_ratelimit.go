@@ -0,0 +1,164 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+// tokenBucket holds state of a single rate limiting group.
+type tokenBucket struct {
+	// tokens is the number of entries that can be accepted immediately.
+	tokens float64
+	// last is the time of the last tokens update.
+	last time.Time
+}
+
+// FilterRateLimit limits number of entries using token bucket algorithm.
+// Every group of entries (see FilterKeyMode) has its own bucket. Bucket is refilled with Rate
+// tokens per second up to Burst tokens. Entry is accepted only if there is a token available
+// in its bucket. Up to MaxGroups buckets are held at the same time. When the limit is exceeded,
+// the bucket of the least recently used group is dropped, so the group starts with a full bucket
+// again.
+//
+// Number of rejected entries is periodically reported with summary entries containing
+// SuppressedProperty and SuppressedKeyProperty. Summary entries are always accepted and are
+// written only by the backend using the filter or by all backends if no backend uses it.
+// Close must be called to stop periodic reporting when filter is no longer used.
+// Pending summary is also logged when Logger is flushed.
+// It implements Filter and Flusher interfaces.
+type FilterRateLimit struct {
+	// Rate defines number of entries per second accepted in a group.
+	Rate float64
+	// Burst defines maximum number of entries that can be accepted at once in a group.
+	Burst int
+	// KeyMode defines how entries are grouped.
+	KeyMode FilterKeyMode
+	// KeyProperty defines name of property used for grouping in FilterKeyModeProperty mode.
+	KeyProperty string
+	// MaxGroups defines maximum number of groups tracked at the same time.
+	// Non-positive value means no limit.
+	MaxGroups int
+
+	// buckets contains token buckets of all groups.
+	buckets *filterGroups
+	// mutex protects buckets from concurrent access.
+	mutex *sync.Mutex
+	// summary collects suppressed entries and reports them.
+	summary *suppressionSummary
+	// now returns current time.
+	now func() time.Time
+}
+
+// NewFilterRateLimit creates and returns a new FilterRateLimit object accepting rate entries
+// per second with bursts of up to burst entries in a single global group. Up to
+// DefaultFilterMaxGroups groups are tracked. Summary of rejected entries is logged every
+// summaryInterval. Reporting is disabled if summaryInterval is not positive.
+func NewFilterRateLimit(rate float64, burst int, summaryInterval time.Duration) *FilterRateLimit {
+	f := &FilterRateLimit{
+		Rate:      rate,
+		Burst:     burst,
+		KeyMode:   FilterKeyModeGlobal,
+		MaxGroups: DefaultFilterMaxGroups,
+		buckets:   newFilterGroups(),
+		mutex:     new(sync.Mutex),
+		now:       time.Now,
+	}
+	f.summary = newSuppressionSummary(f, summaryInterval)
+	return f
+}
+
+// SetSummaryLogger sets Logger used for logging summary entries.
+// By default summaries are logged to the Logger of the last rejected entry.
+func (f *FilterRateLimit) SetSummaryLogger(l *Logger) {
+	f.summary.setLogger(l)
+}
+
+// Suppressed returns number of entries rejected in every group since the last summary.
+func (f *FilterRateLimit) Suppressed() map[string]uint64 {
+	return f.summary.suppressed()
+}
+
+// Summarize logs summary of rejected entries immediately and resets counters.
+func (f *FilterRateLimit) Summarize() {
+	f.summary.report()
+}
+
+// Flush logs pending summary of rejected entries, so it is not lost when Logger is flushed
+// before the process exits. It implements Flusher interface in FilterRateLimit.
+func (f *FilterRateLimit) Flush() error {
+	f.summary.report()
+	return nil
+}
+
+// Close stops periodic reporting and logs pending summary.
+func (f *FilterRateLimit) Close() error {
+	f.summary.close()
+	return nil
+}
+
+// take removes a single token from the bucket of given group.
+// It returns false if bucket is empty.
+func (f *FilterRateLimit) take(key string) bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	now := f.now()
+	state, ok := f.buckets.get(key)
+	b, _ := state.(*tokenBucket)
+	if !ok {
+		b = &tokenBucket{
+			tokens: float64(f.Burst),
+			last:   now,
+		}
+		f.buckets.add(key, b, f.MaxGroups)
+	}
+	b.tokens += now.Sub(b.last).Seconds() * f.Rate
+	if b.tokens > float64(f.Burst) {
+		b.tokens = float64(f.Burst)
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Verify accepts entry if there is a token available in its group.
+// It implements Filter interface in FilterRateLimit type.
+func (f *FilterRateLimit) Verify(entry *Entry) (bool, error) {
+	if entry == nil {
+		return false, ErrInvalidEntry
+	}
+	if isSummary(entry) {
+		return true, nil
+	}
+	key := filterKey(f.KeyMode, f.KeyProperty, entry)
+	if f.take(key) {
+		return true, nil
+	}
+	f.summary.suppress(key, entry)
+	return false, nil
+}
+
+// UsesCallContext returns true if entries are grouped by call context.
+// It implements CallContextUser interface in FilterRateLimit type.
+func (f *FilterRateLimit) UsesCallContext() bool {
+	return f.KeyMode == FilterKeyModeCallContext
+}
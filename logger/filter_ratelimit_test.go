@@ -0,0 +1,189 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FilterRateLimit", func() {
+	var (
+		f   *FilterRateLimit
+		now time.Time
+		e   *Entry
+	)
+
+	BeforeEach(func() {
+		f = NewFilterRateLimit(2, 3, 0)
+		now = time.Unix(1234567890, 0)
+		f.now = func() time.Time { return now }
+		e = &Entry{
+			Level:      WarningLevel,
+			Message:    "message",
+			Properties: Properties{"dryad": "A"},
+		}
+	})
+	AfterEach(func() {
+		Expect(f.Close()).To(Succeed())
+	})
+
+	verifyN := func(entry *Entry, n int) (accepted int) {
+		for i := 0; i < n; i++ {
+			pass, err := f.Verify(entry)
+			Expect(err).NotTo(HaveOccurred())
+			if pass {
+				accepted++
+			}
+		}
+		return accepted
+	}
+
+	Describe("NewFilterRateLimit", func() {
+		It("should create a new object with given configuration", func() {
+			Expect(f).NotTo(BeNil())
+			Expect(f.Rate).To(Equal(2.0))
+			Expect(f.Burst).To(Equal(3))
+			Expect(f.KeyMode).To(Equal(FilterKeyModeGlobal))
+			Expect(f.MaxGroups).To(Equal(DefaultFilterMaxGroups))
+			Expect(f.Suppressed()).To(BeEmpty())
+		})
+	})
+	Describe("Verify", func() {
+		It("should accept burst and reject following entries", func() {
+			Expect(verifyN(e, 10)).To(Equal(3))
+			Expect(f.Suppressed()).To(Equal(map[string]uint64{"": 7}))
+		})
+		It("should refill tokens over time", func() {
+			Expect(verifyN(e, 3)).To(Equal(3))
+			Expect(verifyN(e, 1)).To(Equal(0))
+
+			now = now.Add(time.Second)
+			Expect(verifyN(e, 5)).To(Equal(2))
+
+			now = now.Add(time.Hour)
+			Expect(verifyN(e, 5)).To(Equal(3))
+		})
+		It("should limit groups independently", func() {
+			f.KeyMode = FilterKeyModeProperty
+			f.KeyProperty = "dryad"
+			other := &Entry{
+				Level:      WarningLevel,
+				Properties: Properties{"dryad": "B"},
+			}
+			Expect(verifyN(e, 5)).To(Equal(3))
+			Expect(verifyN(other, 4)).To(Equal(3))
+			Expect(f.Suppressed()).To(Equal(map[string]uint64{"A": 2, "B": 1}))
+		})
+		It("should always accept summary entries", func() {
+			Expect(verifyN(e, 3)).To(Equal(3))
+			summary := &Entry{summaryOf: f}
+			Expect(verifyN(summary, 5)).To(Equal(5))
+		})
+		It("should limit entries with suppressed property created by user", func() {
+			Expect(verifyN(e, 3)).To(Equal(3))
+			user := &Entry{Properties: Properties{SuppressedProperty: 1}}
+			Expect(verifyN(user, 5)).To(Equal(0))
+		})
+		It("should drop bucket of the least recently used group", func() {
+			f.KeyMode = FilterKeyModeProperty
+			f.KeyProperty = "dryad"
+			f.MaxGroups = 2
+			entries := make([]*Entry, 3)
+			for i, dryad := range []string{"A", "B", "C"} {
+				entries[i] = &Entry{Properties: Properties{"dryad": dryad}}
+				Expect(verifyN(entries[i], 4)).To(Equal(3))
+			}
+			Expect(f.buckets.len()).To(Equal(2))
+			Expect(verifyN(entries[2], 1)).To(Equal(0))
+			Expect(verifyN(entries[0], 4)).To(Equal(3))
+		})
+		It("should return error for nil entry", func() {
+			pass, err := f.Verify(nil)
+			Expect(err).To(Equal(ErrInvalidEntry))
+			Expect(pass).To(BeFalse())
+		})
+	})
+	Describe("Summarize", func() {
+		It("should log summary of suppressed entries to the Logger", func() {
+			w := new(writerCollector)
+			L := NewLogger()
+			L.AddBackend("backend", Backend{
+				Filter:     f,
+				Serializer: newPlainSerializerText(),
+				Writer:     w,
+			})
+			for i := 0; i < 5; i++ {
+				L.WithProperty("dryad", "A").Warning("message")
+			}
+			f.Summarize()
+
+			Expect(w.Messages()).To(Equal([]string{
+				`[WAR] message {dryad:A;}`,
+				`[WAR] message {dryad:A;}`,
+				`[WAR] message {dryad:A;}`,
+				`[WAR] "Log entries suppressed." {suppressed:2;suppressed_key:"";}`,
+			}))
+			Expect(f.Suppressed()).To(BeEmpty())
+		})
+	})
+	Describe("Flush", func() {
+		It("should log pending summary when Logger is flushed", func() {
+			w := new(writerCollector)
+			L := NewLogger()
+			L.AddBackend("backend", Backend{
+				Filter:     f,
+				Serializer: newPlainSerializerText(),
+				Writer:     w,
+			})
+			for i := 0; i < 4; i++ {
+				L.Warning("message")
+			}
+			Expect(L.Flush()).To(Succeed())
+			Expect(w.Messages()).To(ContainElement(
+				`[WAR] "Log entries suppressed." {suppressed:1;suppressed_key:"";}`))
+			Expect(f.Suppressed()).To(BeEmpty())
+		})
+		It("should log pending summary when Logger with wrapping backend is flushed", func() {
+			w := new(writerCollector)
+			L := NewLogger()
+			d := NewBackendDedup(Backend{
+				Filter:     f,
+				Serializer: newPlainSerializerText(),
+				Writer:     w,
+			}, 0)
+			defer d.Close()
+			L.AddBackend("backend", NewBackendFingersCrossed(d.Backend(), EmergLevel).Backend())
+			for i := 0; i < 4; i++ {
+				L.Emergency("message")
+			}
+			Expect(L.Flush()).To(Succeed())
+			Expect(w.Messages()).To(ContainElement(
+				`[EME] "Log entries suppressed." {suppressed:1;suppressed_key:"";}`))
+			Expect(f.Suppressed()).To(BeEmpty())
+		})
+	})
+	Describe("UsesCallContext", func() {
+		It("should return true only if entries are grouped by call context", func() {
+			Expect(f.UsesCallContext()).To(BeFalse())
+			f.KeyMode = FilterKeyModeCallContext
+			Expect(f.UsesCallContext()).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,194 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// samplingCounter holds state of a single sampling group.
+type samplingCounter struct {
+	// count is the number of entries seen in current interval.
+	count uint64
+	// start is the beginning of current interval.
+	start time.Time
+}
+
+// FilterSampling accepts only a sample of entries.
+// Every group of entries (see FilterKeyMode) is sampled independently.
+//
+// If Probability is positive, every entry is accepted with that probability. Otherwise
+// the First entries in every Interval are accepted and after that only every Thereafter-th
+// entry is accepted. If Thereafter is 0, no more entries are accepted until the end
+// of the interval. Up to MaxGroups groups are tracked at the same time. When the limit
+// is exceeded, state of the least recently used group is dropped, so its interval starts again.
+//
+// Number of rejected entries is periodically reported with summary entries containing
+// SuppressedProperty and SuppressedKeyProperty. Summary entries are always accepted and are
+// written only by the backend using the filter or by all backends if no backend uses it.
+// Close must be called to stop periodic reporting when filter is no longer used.
+// Pending summary is also logged when Logger is flushed.
+// It implements Filter and Flusher interfaces.
+type FilterSampling struct {
+	// Interval defines length of sampling period.
+	Interval time.Duration
+	// First defines number of entries accepted at the beginning of every interval.
+	First uint64
+	// Thereafter defines how often entries are accepted after First entries.
+	Thereafter uint64
+	// Probability defines probability of accepting an entry in probabilistic mode.
+	Probability float64
+	// KeyMode defines how entries are grouped.
+	KeyMode FilterKeyMode
+	// KeyProperty defines name of property used for grouping in FilterKeyModeProperty mode.
+	KeyProperty string
+	// MaxGroups defines maximum number of groups tracked at the same time.
+	// Non-positive value means no limit.
+	MaxGroups int
+
+	// counters contains sampling state of all groups.
+	counters *filterGroups
+	// mutex protects counters and rand from concurrent access.
+	mutex *sync.Mutex
+	// summary collects suppressed entries and reports them.
+	summary *suppressionSummary
+	// now returns current time.
+	now func() time.Time
+	// rand is the source of randomness for probabilistic mode.
+	rand *rand.Rand
+}
+
+// newFilterSampling creates and returns a new FilterSampling object with initialized
+// internal state tracking up to DefaultFilterMaxGroups groups.
+func newFilterSampling(summaryInterval time.Duration) *FilterSampling {
+	f := &FilterSampling{
+		KeyMode:   FilterKeyModeGlobal,
+		MaxGroups: DefaultFilterMaxGroups,
+		counters:  newFilterGroups(),
+		mutex:     new(sync.Mutex),
+		now:       time.Now,
+		// Sampling does not require cryptographically secure randomness.
+		rand: rand.New(rand.NewSource(time.Now().UnixNano())), // nolint: gosec
+	}
+	f.summary = newSuppressionSummary(f, summaryInterval)
+	return f
+}
+
+// NewFilterSampling creates and returns a new FilterSampling object accepting first entries
+// in every interval and every thereafter-th entry after that in a single global group.
+// Summary of rejected entries is logged every summaryInterval. Reporting is disabled
+// if summaryInterval is not positive.
+func NewFilterSampling(interval time.Duration, first, thereafter uint64,
+	summaryInterval time.Duration) *FilterSampling {
+
+	f := newFilterSampling(summaryInterval)
+	f.Interval = interval
+	f.First = first
+	f.Thereafter = thereafter
+	return f
+}
+
+// NewFilterSamplingProbability creates and returns a new FilterSampling object accepting
+// entries with given probability in a single global group.
+// Summary of rejected entries is logged every summaryInterval. Reporting is disabled
+// if summaryInterval is not positive.
+func NewFilterSamplingProbability(probability float64,
+	summaryInterval time.Duration) *FilterSampling {
+
+	f := newFilterSampling(summaryInterval)
+	f.Probability = probability
+	return f
+}
+
+// SetSummaryLogger sets Logger used for logging summary entries.
+// By default summaries are logged to the Logger of the last rejected entry.
+func (f *FilterSampling) SetSummaryLogger(l *Logger) {
+	f.summary.setLogger(l)
+}
+
+// Suppressed returns number of entries rejected in every group since the last summary.
+func (f *FilterSampling) Suppressed() map[string]uint64 {
+	return f.summary.suppressed()
+}
+
+// Summarize logs summary of rejected entries immediately and resets counters.
+func (f *FilterSampling) Summarize() {
+	f.summary.report()
+}
+
+// Flush logs pending summary of rejected entries, so it is not lost when Logger is flushed
+// before the process exits. It implements Flusher interface in FilterSampling.
+func (f *FilterSampling) Flush() error {
+	f.summary.report()
+	return nil
+}
+
+// Close stops periodic reporting and logs pending summary.
+func (f *FilterSampling) Close() error {
+	f.summary.close()
+	return nil
+}
+
+// sample decides if next entry in given group should be accepted.
+func (f *FilterSampling) sample(key string) bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.Probability > 0 {
+		return f.rand.Float64() < f.Probability
+	}
+
+	now := f.now()
+	state, ok := f.counters.get(key)
+	c, _ := state.(*samplingCounter)
+	if !ok {
+		c = &samplingCounter{start: now}
+		f.counters.add(key, c, f.MaxGroups)
+	} else if f.Interval > 0 && now.Sub(c.start) >= f.Interval {
+		*c = samplingCounter{start: now}
+	}
+	c.count++
+	if c.count <= f.First {
+		return true
+	}
+	return f.Thereafter > 0 && (c.count-f.First)%f.Thereafter == 0
+}
+
+// Verify accepts entry if it is chosen to the sample of its group.
+// It implements Filter interface in FilterSampling type.
+func (f *FilterSampling) Verify(entry *Entry) (bool, error) {
+	if entry == nil {
+		return false, ErrInvalidEntry
+	}
+	if isSummary(entry) {
+		return true, nil
+	}
+	key := filterKey(f.KeyMode, f.KeyProperty, entry)
+	if f.sample(key) {
+		return true, nil
+	}
+	f.summary.suppress(key, entry)
+	return false, nil
+}
+
+// UsesCallContext returns true if entries are grouped by call context.
+// It implements CallContextUser interface in FilterSampling type.
+func (f *FilterSampling) UsesCallContext() bool {
+	return f.KeyMode == FilterKeyModeCallContext
+}
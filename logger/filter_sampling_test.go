@@ -0,0 +1,165 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FilterSampling", func() {
+	var (
+		f   *FilterSampling
+		now time.Time
+		e   *Entry
+	)
+
+	BeforeEach(func() {
+		f = NewFilterSampling(time.Second, 3, 4, 0)
+		now = time.Unix(1234567890, 0)
+		f.now = func() time.Time { return now }
+		e = &Entry{
+			Level:   DebugLevel,
+			Message: "message",
+		}
+	})
+	AfterEach(func() {
+		Expect(f.Close()).To(Succeed())
+	})
+
+	verify := func(entry *Entry, n int) (ret []bool) {
+		for i := 0; i < n; i++ {
+			pass, err := f.Verify(entry)
+			Expect(err).NotTo(HaveOccurred())
+			ret = append(ret, pass)
+		}
+		return ret
+	}
+
+	Describe("NewFilterSampling", func() {
+		It("should create a new object with given configuration", func() {
+			Expect(f).NotTo(BeNil())
+			Expect(f.Interval).To(Equal(time.Second))
+			Expect(f.First).To(Equal(uint64(3)))
+			Expect(f.Thereafter).To(Equal(uint64(4)))
+			Expect(f.Probability).To(BeZero())
+			Expect(f.MaxGroups).To(Equal(DefaultFilterMaxGroups))
+		})
+	})
+	Describe("NewFilterSamplingProbability", func() {
+		It("should create a new object with given probability", func() {
+			p := NewFilterSamplingProbability(0.5, 0)
+			defer p.Close()
+			Expect(p).NotTo(BeNil())
+			Expect(p.Probability).To(Equal(0.5))
+		})
+	})
+	Describe("Verify", func() {
+		It("should accept first entries and every n-th after that", func() {
+			Expect(verify(e, 12)).To(Equal([]bool{
+				true, true, true,
+				false, false, false, true,
+				false, false, false, true,
+				false,
+			}))
+			Expect(f.Suppressed()).To(Equal(map[string]uint64{"": 7}))
+		})
+		It("should reset sampling after interval", func() {
+			Expect(verify(e, 4)).To(Equal([]bool{true, true, true, false}))
+			now = now.Add(time.Second)
+			Expect(verify(e, 4)).To(Equal([]bool{true, true, true, false}))
+		})
+		It("should reject all entries after first ones if thereafter is 0", func() {
+			f.Thereafter = 0
+			Expect(verify(e, 6)).To(Equal([]bool{true, true, true, false, false, false}))
+		})
+		It("should sample groups independently", func() {
+			f.KeyMode = FilterKeyModeLevel
+			other := &Entry{Level: ErrLevel}
+			Expect(verify(e, 4)).To(Equal([]bool{true, true, true, false}))
+			Expect(verify(other, 4)).To(Equal([]bool{true, true, true, false}))
+			Expect(f.Suppressed()).To(Equal(map[string]uint64{"debug": 1, "error": 1}))
+		})
+		It("should accept entries with given probability", func() {
+			f = NewFilterSamplingProbability(0.25, 0)
+			const n = 10000
+			accepted := 0
+			for _, pass := range verify(e, n) {
+				if pass {
+					accepted++
+				}
+			}
+			Expect(accepted).To(BeNumerically("~", n/4, n/20))
+		})
+		It("should accept all entries if probability is 1", func() {
+			f = NewFilterSamplingProbability(1, 0)
+			Expect(verify(e, 3)).To(Equal([]bool{true, true, true}))
+		})
+		It("should always accept summary entries", func() {
+			f.First = 0
+			summary := &Entry{summaryOf: f}
+			Expect(verify(summary, 3)).To(Equal([]bool{true, true, true}))
+			user := &Entry{Properties: Properties{SuppressedProperty: 1}}
+			Expect(verify(user, 1)).To(Equal([]bool{false}))
+		})
+		It("should drop state of the least recently used group", func() {
+			f.KeyMode = FilterKeyModeProperty
+			f.KeyProperty = "dryad"
+			f.MaxGroups = 2
+			entries := make([]*Entry, 3)
+			for i, dryad := range []string{"A", "B", "C"} {
+				entries[i] = &Entry{Properties: Properties{"dryad": dryad}}
+				Expect(verify(entries[i], 4)).To(Equal([]bool{true, true, true, false}))
+			}
+			Expect(f.counters.len()).To(Equal(2))
+			Expect(verify(entries[0], 1)).To(Equal([]bool{true}))
+		})
+		It("should return error for nil entry", func() {
+			pass, err := f.Verify(nil)
+			Expect(err).To(Equal(ErrInvalidEntry))
+			Expect(pass).To(BeFalse())
+		})
+	})
+	Describe("Flush", func() {
+		It("should log pending summary when Logger is flushed", func() {
+			w := new(writerCollector)
+			L := NewLogger()
+			L.SetThreshold(DebugLevel)
+			L.AddBackend("backend", Backend{
+				Filter:     f,
+				Serializer: newPlainSerializerText(),
+				Writer:     w,
+			})
+			for i := 0; i < 4; i++ {
+				L.Debug("message")
+			}
+			Expect(L.Flush()).To(Succeed())
+			Expect(w.Messages()).To(ContainElement(
+				`[DEB] "Log entries suppressed." {suppressed:1;suppressed_key:"";}`))
+			Expect(f.Suppressed()).To(BeEmpty())
+		})
+	})
+	Describe("UsesCallContext", func() {
+		It("should return true only if entries are grouped by call context", func() {
+			Expect(f.UsesCallContext()).To(BeFalse())
+			f.KeyMode = FilterKeyModeCallContext
+			Expect(f.UsesCallContext()).To(BeTrue())
+		})
+	})
+})
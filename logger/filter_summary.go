@@ -0,0 +1,217 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FilterKeyMode defines how entries are grouped by limiting filters.
+// Every group of entries is limited independently.
+type FilterKeyMode uint8
+
+const (
+	// FilterKeyModeGlobal - all entries share a single group.
+	FilterKeyModeGlobal FilterKeyMode = iota
+	// FilterKeyModeLevel - entries are grouped by their level.
+	FilterKeyModeLevel
+	// FilterKeyModeCallContext - entries are grouped by source file and line of log creation.
+	FilterKeyModeCallContext
+	// FilterKeyModeProperty - entries are grouped by value of a chosen property.
+	FilterKeyModeProperty
+	// FilterKeyModeContext - entries are grouped by scope carried by context used to create them
+	// (see ContextWithScope and WithContext).
+	FilterKeyModeContext
+)
+
+const (
+	// SuppressedProperty defines key of property holding number of suppressed entries
+	// in summary entries created by limiting filters.
+	SuppressedProperty = "suppressed"
+	// SuppressedKeyProperty defines key of property holding the group of suppressed entries
+	// in summary entries created by limiting filters.
+	SuppressedKeyProperty = "suppressed_key"
+	// suppressedMessage is the message of summary entries created by limiting filters.
+	suppressedMessage = "Log entries suppressed."
+)
+
+// filterKey returns a key of a group to which entry belongs.
+// Property name is used only in FilterKeyModeProperty mode.
+func filterKey(mode FilterKeyMode, property string, entry *Entry) string {
+	switch mode {
+	case FilterKeyModeLevel:
+		return entry.Level.String()
+	case FilterKeyModeCallContext:
+		if entry.CallContext == nil {
+			return ""
+		}
+		return fmt.Sprintf("%s%s:%d", entry.CallContext.Path, entry.CallContext.File,
+			entry.CallContext.Line)
+	case FilterKeyModeProperty:
+		v, ok := entry.property(property)
+		if !ok {
+			return ""
+		}
+		return fmt.Sprint(v)
+	case FilterKeyModeContext:
+		return entry.scope
+	case FilterKeyModeGlobal:
+	default:
+	}
+	return ""
+}
+
+// isSummary verifies if entry is a summary of suppressed entries created by a limiting filter.
+// Such entries are never limited, so information about suppressed entries is not lost.
+func isSummary(entry *Entry) bool {
+	return entry.summaryOf != nil
+}
+
+// suppressedCounter counts entries suppressed within a single group.
+type suppressedCounter struct {
+	// count is the number of suppressed entries.
+	count uint64
+	// level is the most important level of suppressed entries.
+	level Level
+}
+
+// suppressionSummary collects information about entries suppressed by limiting filters
+// and periodically logs summary entries. Summaries are passed only to the backend using
+// the filter (or to all backends if no backend uses it), without call context.
+type suppressionSummary struct {
+	// filter is the limiting filter, which suppressed entries.
+	filter Filter
+	// logger is used for logging summary entries. If it is nil, the Logger of the last
+	// suppressed entry is used.
+	logger *Logger
+	// last is the Logger of the last suppressed entry.
+	last *Logger
+	// counters contains suppressed entries counters for every group.
+	counters map[string]*suppressedCounter
+	// mutex protects suppressionSummary structure from concurrent access.
+	mutex *sync.Mutex
+	// done stops periodic reporting.
+	done chan struct{}
+	// closeOnce ensures that periodic reporting is stopped only once.
+	closeOnce sync.Once
+}
+
+// newSuppressionSummary creates a new suppressionSummary of filter reporting every interval.
+// Periodic reporting is disabled if interval is not positive.
+func newSuppressionSummary(filter Filter, interval time.Duration) *suppressionSummary {
+	s := &suppressionSummary{
+		filter:   filter,
+		counters: make(map[string]*suppressedCounter),
+		mutex:    new(sync.Mutex),
+		done:     make(chan struct{}),
+	}
+	if interval > 0 {
+		go s.loop(interval)
+	}
+	return s
+}
+
+// loop logs summary every interval until summary is closed.
+func (s *suppressionSummary) loop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.report()
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// setLogger sets Logger used for reporting summary entries.
+func (s *suppressionSummary) setLogger(l *Logger) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.logger = l
+}
+
+// suppress records that entry was suppressed in the group identified by key.
+func (s *suppressionSummary) suppress(key string, entry *Entry) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	c, ok := s.counters[key]
+	if !ok {
+		c = &suppressedCounter{level: entry.Level}
+		s.counters[key] = c
+	}
+	c.count++
+	if entry.Level < c.level {
+		c.level = entry.Level
+	}
+	if entry.Logger != nil {
+		s.last = entry.Logger
+	}
+}
+
+// suppressed returns number of suppressed entries for every group since last summary.
+func (s *suppressionSummary) suppressed() map[string]uint64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	ret := make(map[string]uint64, len(s.counters))
+	for k, c := range s.counters {
+		ret[k] = c.count
+	}
+	return ret
+}
+
+// report logs a summary entry for every group with suppressed entries and resets counters.
+// Summary entry's level is the most important level of suppressed entries in a group.
+func (s *suppressionSummary) report() {
+	s.mutex.Lock()
+	counters := s.counters
+	s.counters = make(map[string]*suppressedCounter)
+	l := s.logger
+	if l == nil {
+		l = s.last
+	}
+	s.mutex.Unlock()
+
+	if l == nil || len(counters) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(counters))
+	for k := range counters {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		e := l.newEntry().withoutCaller().WithProperties(Properties{
+			SuppressedProperty:    counters[k].count,
+			SuppressedKeyProperty: k,
+		})
+		e.summaryOf = s.filter
+		e.Log(counters[k].level, suppressedMessage)
+	}
+}
+
+// close stops periodic reporting and reports pending summary.
+func (s *suppressionSummary) close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		s.report()
+	})
+}
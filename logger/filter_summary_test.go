@@ -0,0 +1,162 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	T "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FilterSummary", func() {
+	var (
+		e      *Entry
+		w      *writerCollector
+		other  *writerCollector
+		L      *Logger
+		filter Filter
+	)
+
+	BeforeEach(func() {
+		e = &Entry{
+			Level:      NoticeLevel,
+			Properties: Properties{"job": 17},
+			scope:      "12",
+			CallContext: &CallContext{
+				Path: "/some/path/",
+				File: "file.go",
+				Line: 42,
+			},
+		}
+		w = new(writerCollector)
+		other = new(writerCollector)
+		filter = NewFilterRateLimit(1000, 1000, 0)
+		L = NewLogger()
+		L.AddBackend("backend", Backend{
+			Filter:     filter,
+			Serializer: newPlainSerializerText(),
+			Writer:     w,
+		})
+		L.AddBackend("other", Backend{
+			Filter:     NewFilterPassAll(),
+			Serializer: newPlainSerializerText(),
+			Writer:     other,
+		})
+	})
+
+	Describe("filterKey", func() {
+		T.DescribeTable("should return key of entry's group",
+			func(mode FilterKeyMode, property, expected string) {
+				Expect(filterKey(mode, property, e)).To(Equal(expected))
+			},
+			T.Entry("global", FilterKeyModeGlobal, "", ""),
+			T.Entry("level", FilterKeyModeLevel, "", "notice"),
+			T.Entry("call context", FilterKeyModeCallContext, "", "/some/path/file.go:42"),
+			T.Entry("property", FilterKeyModeProperty, "job", "17"),
+			T.Entry("missing property", FilterKeyModeProperty, "dryad", ""),
+			T.Entry("context", FilterKeyModeContext, "", "12"),
+			T.Entry("invalid mode", FilterKeyMode(0xFF), "", ""),
+		)
+		It("should handle missing call context", func() {
+			e.CallContext = nil
+			Expect(filterKey(FilterKeyModeCallContext, "", e)).To(BeEmpty())
+		})
+	})
+	Describe("suppressionSummary", func() {
+		It("should report the most important level of suppressed entries", func() {
+			s := newSuppressionSummary(filter, 0)
+			s.setLogger(L)
+			s.suppress("key", e)
+			s.suppress("key", &Entry{Level: ErrLevel})
+			s.suppress("key", &Entry{Level: DebugLevel})
+			s.report()
+
+			Expect(w.Levels()).To(Equal([]Level{ErrLevel}))
+			Expect(w.Messages()).To(Equal([]string{
+				`[ERR] "Log entries suppressed." {suppressed:3;suppressed_key:key;}`,
+			}))
+		})
+		It("should use Logger of the last suppressed entry by default", func() {
+			s := newSuppressionSummary(filter, 0)
+			e.Logger = L
+			s.suppress("a", e)
+			s.suppress("b", e)
+			s.close()
+
+			Expect(w.Messages()).To(Equal([]string{
+				`[NOT] "Log entries suppressed." {suppressed:1;suppressed_key:a;}`,
+				`[NOT] "Log entries suppressed." {suppressed:1;suppressed_key:b;}`,
+			}))
+		})
+		It("should pass summary only to the backend using the filter without caller", func() {
+			f := new(filterCollector)
+			L.AddBackend("backend", Backend{
+				Filter:     NewBackendDedup(Backend{Filter: filter}, 0),
+				Serializer: newPlainSerializerText(),
+				Writer:     w,
+			})
+			L.AddBackend("collector", Backend{
+				Filter:     f,
+				Serializer: newPlainSerializerText(),
+				Writer:     new(writerCollector),
+			})
+			s := newSuppressionSummary(filter, 0)
+			s.setLogger(L)
+			s.suppress("key", e)
+			s.report()
+
+			Expect(w.Messages()).To(HaveLen(1))
+			Expect(other.Messages()).To(BeEmpty())
+			Expect(f.Entries()).To(BeEmpty())
+		})
+		It("should mark summaries with the filter", func() {
+			f := new(filterCollector)
+			L.AddBackend("backend", Backend{
+				Filter:     f,
+				Serializer: newPlainSerializerText(),
+				Writer:     w,
+			})
+			s := newSuppressionSummary(f, 0)
+			s.setLogger(L)
+			s.suppress("key", e)
+			s.report()
+
+			entries := f.Entries()
+			Expect(entries).To(HaveLen(1))
+			Expect(isSummary(entries[0])).To(BeTrue())
+			Expect(entries[0].CallContext).To(BeNil())
+			Expect(isSummary(e)).To(BeFalse())
+		})
+		It("should not report anything without suppressed entries", func() {
+			s := newSuppressionSummary(filter, 0)
+			s.setLogger(L)
+			s.report()
+			Expect(w.Messages()).To(BeEmpty())
+		})
+		It("should report periodically", func() {
+			s := newSuppressionSummary(filter, 10*time.Millisecond)
+			defer s.close()
+			s.setLogger(L)
+			s.suppress("key", e)
+
+			Eventually(w.Messages).Should(HaveLen(1))
+			Expect(s.suppressed()).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,73 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	// helpers contains map[string]struct{} with full names of functions marked with Helper.
+	// The map is never modified after being stored; it is replaced as a whole (copy-on-write),
+	// so it can be read without locking.
+	helpers atomic.Value
+	// helpersMutex serializes modifications of helpers.
+	helpersMutex sync.Mutex
+)
+
+// Helper marks the calling function as a logging helper function. Helper functions are skipped
+// when call context is captured, so the context of their caller is logged instead. Helpers can be
+// nested and marking is effective in all goroutines. It is analogous to testing.T.Helper and
+// can be used instead of IncDepth when a wrapper does not want to count its stack depth.
+// Calling Helper many times from the same function is cheap.
+func Helper() {
+	var pcs [1]uintptr
+	if runtime.Callers(2, pcs[:]) < 1 {
+		return
+	}
+	function := resolveCallSite(pcs[0]).function
+	if isHelper(function) {
+		return
+	}
+
+	helpersMutex.Lock()
+	defer helpersMutex.Unlock()
+	current, _ := helpers.Load().(map[string]struct{})
+	if _, ok := current[function]; ok {
+		return
+	}
+	marked := make(map[string]struct{}, len(current)+1)
+	for k := range current {
+		marked[k] = struct{}{}
+	}
+	marked[function] = struct{}{}
+	helpers.Store(marked)
+}
+
+// helpersMarked returns true if any function has been marked with Helper.
+func helpersMarked() bool {
+	return helpers.Load() != nil
+}
+
+// isHelper returns true if function with given full name has been marked with Helper.
+func isHelper(function string) bool {
+	marked, _ := helpers.Load().(map[string]struct{})
+	_, ok := marked[function]
+	return ok
+}
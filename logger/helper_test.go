@@ -0,0 +1,111 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"reflect"
+	"runtime"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func logHelper(l *Logger, msg string) {
+	Helper()
+	l.Info(msg)
+}
+
+func nestedLogHelper(l *Logger, msg string) {
+	Helper()
+	logHelper(l, msg)
+}
+
+func contextHelper() *CallContext {
+	Helper()
+	return getCallContext(0)
+}
+
+func notHelper() *CallContext {
+	return contextHelper()
+}
+
+// markedAsHelper returns true if function f has been marked with Helper.
+func markedAsHelper(f interface{}) bool {
+	return isHelper(runtime.FuncForPC(reflect.ValueOf(f).Pointer()).Name())
+}
+
+var _ = Describe("Helper", func() {
+	const thisFile = "helper_test.go"
+	var (
+		L *Logger
+		m *Metrics
+	)
+
+	BeforeEach(func() {
+		L = NewLogger()
+		m = NewMetrics()
+		L.AddBackend("metrics", m.Backend("helper"))
+	})
+
+	expectCallSite := func(line int) {
+		top := m.TopTalkers(0)
+		Expect(top).To(HaveLen(1))
+		Expect(top[0].File).To(Equal(thisFile))
+		Expect(top[0].Line).To(Equal(line))
+	}
+
+	It("should skip helper function", func() {
+		_, _, line, _ := runtime.Caller(0)
+		logHelper(L, "message")
+		expectCallSite(line + 1)
+	})
+	It("should skip nested helper functions", func() {
+		_, _, line, _ := runtime.Caller(0)
+		nestedLogHelper(L, "message")
+		expectCallSite(line + 1)
+	})
+	It("should stop at first function not marked as helper", func() {
+		ctx := notHelper()
+		Expect(ctx).NotTo(BeNil())
+		Expect(ctx.Function).To(Equal("notHelper"))
+	})
+	It("should work across goroutines", func() {
+		logHelper(L, "mark")
+		m = NewMetrics()
+		L.AddBackend("metrics", m.Backend("helper"))
+
+		var line int
+		wg := new(sync.WaitGroup)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, line, _ = runtime.Caller(0)
+			logHelper(L, "message")
+		}()
+		wg.Wait()
+		expectCallSite(line + 1)
+	})
+	It("should mark function only once", func() {
+		logHelper(L, "mark")
+		marked := reflect.ValueOf(helpers.Load()).Pointer()
+		logHelper(L, "again")
+		Expect(reflect.ValueOf(helpers.Load()).Pointer()).To(Equal(marked))
+		Expect(isHelper(thisPackage + ".logHelper")).To(BeTrue())
+		Expect(isHelper(thisPackage + ".notHelper")).To(BeFalse())
+	})
+})
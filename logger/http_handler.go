@@ -0,0 +1,243 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+const (
+	// RequestIDProperty defines key of field holding ID of the HTTP request.
+	RequestIDProperty = "request_id"
+	// MethodProperty defines key of field holding method of the HTTP request.
+	MethodProperty = "method"
+	// PathProperty defines key of field holding URL path of the HTTP request.
+	PathProperty = "path"
+	// StatusProperty defines key of field holding status code of the HTTP response.
+	StatusProperty = "status"
+	// BytesProperty defines key of field holding size of the HTTP response body.
+	BytesProperty = "bytes"
+	// RemoteAddrProperty defines key of field holding network address of the HTTP client.
+	RemoteAddrProperty = "remote_addr"
+)
+
+// DefaultRequestIDHeader is the default header carrying ID of the HTTP request.
+const DefaultRequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength limits length of request IDs taken from headers. Longer IDs are replaced
+// with generated ones.
+const maxRequestIDLength = 128
+
+// HTTPHandler is a net/http middleware logging every handled request. The request ID is taken
+// from RequestIDHeader or generated, and set in headers of both the response and the request
+// passed to Handler. The request provided by the server is not modified. Request passed to Handler
+// carries Logger and request ID property in its context, so entries created with
+// WithContext(r.Context()) can be correlated with the request. If Trace is set, the context
+// carries also TraceContext of a new span within the trace of the request (see
+// TraceFromHTTPHeader) or of a new trace. If Handler panics, request is logged with
+// PanicProperty and 500 status code (unless other was written) before panicking again.
+// Handled requests have no meaningful call site, so they are logged without call context.
+// It implements http.Handler interface.
+type HTTPHandler struct {
+	// Handler handles requests.
+	Handler http.Handler
+	// Logger logs requests.
+	Logger *Logger
+	// RequestIDHeader is the header carrying request ID.
+	RequestIDHeader string
+	// Trace set to true makes requests handled within W3C trace context.
+	Trace bool
+	// SuccessLevel is the level of requests with 1xx, 2xx and 3xx status codes.
+	SuccessLevel Level
+	// ClientErrorLevel is the level of requests with 4xx status codes.
+	ClientErrorLevel Level
+	// ServerErrorLevel is the level of requests with 5xx status codes.
+	ServerErrorLevel Level
+	// Sampler, if set, decides which successful requests are logged (e.g. FilterSampling).
+	// Failed requests (with 4xx and 5xx status codes) are always logged.
+	Sampler Filter
+}
+
+// NewHTTPHandler creates and returns a new HTTPHandler logging requests handled by h to l
+// with trace context. Successful requests are logged with InfoLevel, client errors
+// with WarningLevel and server errors with ErrLevel.
+func NewHTTPHandler(l *Logger, h http.Handler) *HTTPHandler {
+	return &HTTPHandler{
+		Handler:          h,
+		Logger:           l,
+		RequestIDHeader:  DefaultRequestIDHeader,
+		Trace:            true,
+		SuccessLevel:     InfoLevel,
+		ClientErrorLevel: WarningLevel,
+		ServerErrorLevel: ErrLevel,
+	}
+}
+
+// ServeHTTP handles request with Handler and logs it. It implements http.Handler interface
+// in HTTPHandler.
+func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	id := r.Header.Get(h.RequestIDHeader)
+	generated := id == "" || len(id) > maxRequestIDLength
+	if generated {
+		id = randomID(requestIDSize)
+	}
+	w.Header().Set(h.RequestIDHeader, id)
+	ctx := ContextWithProperties(r.Context(), Properties{RequestIDProperty: id})
+	if h.Trace {
+		ctx = ContextWithTrace(ctx, requestTrace(r))
+	}
+	r = r.WithContext(NewContext(ctx, h.Logger))
+	if generated {
+		// Request provided by the caller must not be modified and its copy shares the header.
+		r.Header = r.Header.Clone()
+		r.Header.Set(h.RequestIDHeader, id)
+	}
+
+	rw := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+	defer func() {
+		if value := recover(); value != nil {
+			if !rw.wroteHeader {
+				rw.status = http.StatusInternalServerError
+			}
+			h.log(r, rw, time.Since(start), String(PanicProperty, fmt.Sprint(value)))
+			panic(value)
+		}
+	}()
+	h.Handler.ServeHTTP(rw, r)
+	h.log(r, rw, time.Since(start))
+}
+
+// log logs handled request with additional fields unless it is successful and rejected
+// by Sampler.
+func (h *HTTPHandler) log(r *http.Request, rw *responseRecorder, duration time.Duration,
+	fields ...Field) {
+
+	e := h.Logger.WithContext(r.Context()).withoutCaller().WithFields(
+		String(MethodProperty, r.Method),
+		String(PathProperty, r.URL.Path),
+		Int(StatusProperty, rw.status),
+		Int(BytesProperty, int(rw.bytes)),
+		Duration(DurationProperty, duration),
+		String(RemoteAddrProperty, r.RemoteAddr),
+	).WithFields(fields...)
+	level := h.level(rw.status)
+	msg := fmt.Sprintf("%s %s %d", r.Method, r.URL.Path, rw.status)
+	if h.Sampler != nil && rw.status < http.StatusBadRequest {
+		e.Level, e.Message = level, msg
+		if ok, err := h.Sampler.Verify(e); err != nil || !ok {
+			return
+		}
+	}
+	e.Log(level, msg)
+}
+
+// requestTrace returns TraceContext of a new span within trace of request r or of a new trace
+// if r has no valid traceparent header.
+func requestTrace(r *http.Request) TraceContext {
+	tc, err := TraceFromHTTPHeader(r.Header)
+	if err != nil {
+		return NewTraceContext()
+	}
+	return tc.NewSpan()
+}
+
+// level returns level of request with given status code.
+func (h *HTTPHandler) level(status int) Level {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return h.ServerErrorLevel
+	case status >= http.StatusBadRequest:
+		return h.ClientErrorLevel
+	}
+	return h.SuccessLevel
+}
+
+// HTTPHandler returns a new HTTPHandler logging requests handled by h.
+func (l *Logger) HTTPHandler(h http.Handler) *HTTPHandler {
+	return NewHTTPHandler(l, h)
+}
+
+// requestIDSize is the number of random bytes of generated request IDs.
+const requestIDSize = 16
+
+// responseRecorder is a http.ResponseWriter recording status code and size of the response.
+type responseRecorder struct {
+	http.ResponseWriter
+	// status is the status code of the response.
+	status int
+	// bytes is the number of written bytes of the response body.
+	bytes int64
+	// wroteHeader is set when header of the response is written.
+	wroteHeader bool
+}
+
+// WriteHeader records status code and writes header of the response. Informational status
+// codes (like 103 Early Hints) are not recorded, as they are followed by the final one.
+// Only 101 Switching Protocols is final.
+func (rw *responseRecorder) WriteHeader(status int) {
+	informational := status >= 100 && status < http.StatusOK &&
+		status != http.StatusSwitchingProtocols
+	if !rw.wroteHeader && !informational {
+		rw.status = status
+		rw.wroteHeader = true
+	}
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+// Write writes and counts bytes of the response body.
+func (rw *responseRecorder) Write(p []byte) (int, error) {
+	rw.wroteHeader = true
+	n, err := rw.ResponseWriter.Write(p)
+	rw.bytes += int64(n)
+	return n, err
+}
+
+// Flush sends buffered data to the client if the wrapped http.ResponseWriter supports it.
+// It implements http.Flusher interface in responseRecorder.
+func (rw *responseRecorder) Flush() {
+	rw.wroteHeader = true
+	if f, ok := rw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack takes over the connection if the wrapped http.ResponseWriter supports it, e.g. for
+// websocket upgrade. Unless other status code was written, 101 Switching Protocols is recorded.
+// It implements http.Hijacker interface in responseRecorder.
+func (rw *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := rw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T does not implement http.Hijacker: %w",
+			rw.ResponseWriter, http.ErrNotSupported)
+	}
+	conn, buf, err := h.Hijack()
+	if err == nil && !rw.wroteHeader {
+		rw.status = http.StatusSwitchingProtocols
+		rw.wroteHeader = true
+	}
+	return conn, buf, err
+}
+
+// Unwrap returns the wrapped http.ResponseWriter. It is used by http.ResponseController.
+func (rw *responseRecorder) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
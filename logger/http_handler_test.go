@@ -0,0 +1,259 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	T "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+// hijackRecorder is a httptest.ResponseRecorder supporting connection hijacking.
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+	conn net.Conn
+}
+
+func (w *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return w.conn, bufio.NewReadWriter(bufio.NewReader(w.conn), bufio.NewWriter(w.conn)), nil
+}
+
+var _ = Describe("HTTPHandler", func() {
+	var (
+		L      *Logger
+		f      *filterCollector
+		status int
+		seen   *http.Request
+		h      *HTTPHandler
+	)
+
+	BeforeEach(func() {
+		L = NewLogger()
+		L.SetThreshold(DebugLevel)
+		f = new(filterCollector)
+		L.AddBackend("collector", Backend{
+			Filter:     f,
+			Serializer: NewSerializerJSON(),
+			Writer:     new(writerCollector),
+		})
+		status = http.StatusOK
+		seen = nil
+		h = L.HTTPHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			seen = r
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte("body"))
+			WithContext(r.Context()).Info("handling")
+		}))
+	})
+
+	serve := func(r *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+	value := func(e *Entry, key string) interface{} {
+		v, _ := e.property(key)
+		return v
+	}
+
+	It("should create a new object with default configuration", func() {
+		Expect(h.Logger).To(Equal(L))
+		Expect(h.RequestIDHeader).To(Equal(DefaultRequestIDHeader))
+		Expect(h.Trace).To(BeTrue())
+		Expect(h.SuccessLevel).To(Equal(InfoLevel))
+		Expect(h.ClientErrorLevel).To(Equal(WarningLevel))
+		Expect(h.ServerErrorLevel).To(Equal(ErrLevel))
+		Expect(h.Sampler).To(BeNil())
+	})
+	It("should log request with generated request ID", func() {
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/reqs?x=1", nil)
+		r.RemoteAddr = "10.0.0.1:1234"
+		w := serve(r)
+
+		id := w.Header().Get(DefaultRequestIDHeader)
+		Expect(id).To(HaveLen(32))
+		Expect(seen.Header.Get(DefaultRequestIDHeader)).To(Equal(id))
+		Expect(r.Header.Get(DefaultRequestIDHeader)).To(BeEmpty())
+		Expect(FromContext(seen.Context())).To(BeIdenticalTo(L))
+
+		entries := f.Entries()
+		Expect(entries).To(HaveLen(2))
+		Expect(entries[0].Message).To(Equal("handling"))
+		Expect(entries[0].Properties).To(HaveKeyWithValue(RequestIDProperty, id))
+		e := entries[1]
+		Expect(e.Level).To(Equal(InfoLevel))
+		Expect(e.Message).To(Equal("GET /api/v1/reqs 200"))
+		Expect(e.Properties).To(HaveKeyWithValue(RequestIDProperty, id))
+		Expect(value(e, MethodProperty)).To(Equal("GET"))
+		Expect(value(e, PathProperty)).To(Equal("/api/v1/reqs"))
+		Expect(value(e, StatusProperty)).To(Equal(http.StatusOK))
+		Expect(value(e, BytesProperty)).To(Equal(4))
+		Expect(value(e, RemoteAddrProperty)).To(Equal("10.0.0.1:1234"))
+		Expect(value(e, DurationProperty)).To(BeNumerically(">", 0))
+		Expect(e.CallContext).To(BeNil())
+	})
+	It("should take request ID from header", func() {
+		h.RequestIDHeader = "X-Trace"
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("X-Trace", "abc")
+		w := serve(r)
+		Expect(w.Header().Get("X-Trace")).To(Equal("abc"))
+		Expect(f.Entries()[1].Properties).To(HaveKeyWithValue(RequestIDProperty, "abc"))
+	})
+	It("should handle request in a new span of its trace", func() {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		r.Header.Set(TracestateHeader, "congo=t61rcWkgMzE")
+		serve(r)
+
+		tc, ok := TraceFromContext(seen.Context())
+		Expect(ok).To(BeTrue())
+		Expect(tc.TraceID).To(Equal("4bf92f3577b34da6a3ce929d0e0e4736"))
+		Expect(tc.SpanID).To(HaveLen(16))
+		Expect(tc.SpanID).NotTo(Equal("00f067aa0ba902b7"))
+		Expect(tc.State).To(Equal("congo=t61rcWkgMzE"))
+		for _, e := range f.Entries() {
+			Expect(e.Properties).To(HaveKeyWithValue(TraceIDProperty, tc.TraceID))
+			Expect(e.Properties).To(HaveKeyWithValue(SpanIDProperty, tc.SpanID))
+		}
+	})
+	It("should start a new trace if request has no valid traceparent", func() {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set(TraceparentHeader, "invalid")
+		serve(r)
+
+		tc, ok := TraceFromContext(seen.Context())
+		Expect(ok).To(BeTrue())
+		Expect(tc.TraceID).To(HaveLen(32))
+		Expect(tc.Flags).To(Equal(TraceFlagSampled))
+	})
+	It("should not trace requests if disabled", func() {
+		h.Trace = false
+		serve(httptest.NewRequest(http.MethodGet, "/", nil))
+		_, ok := TraceFromContext(seen.Context())
+		Expect(ok).To(BeFalse())
+		Expect(f.Entries()[1].Properties).To(Equal(Properties{
+			RequestIDProperty: f.Entries()[1].Properties[RequestIDProperty],
+		}))
+	})
+	It("should replace too long request ID", func() {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set(DefaultRequestIDHeader, fmt.Sprintf("%0129d", 0))
+		w := serve(r)
+		Expect(w.Header().Get(DefaultRequestIDHeader)).To(HaveLen(32))
+	})
+	T.DescribeTable("should choose level by status class",
+		func(code int, expected Level) {
+			status = code
+			serve(httptest.NewRequest(http.MethodPost, "/", nil))
+			Expect(f.Entries()[1].Level).To(Equal(expected))
+		},
+		T.Entry("informational", http.StatusSwitchingProtocols, InfoLevel),
+		T.Entry("success", http.StatusCreated, InfoLevel),
+		T.Entry("redirection", http.StatusFound, InfoLevel),
+		T.Entry("client error", http.StatusNotFound, WarningLevel),
+		T.Entry("server error", http.StatusServiceUnavailable, ErrLevel),
+	)
+	It("should sample successful requests only", func() {
+		var sampled []string
+		h.Sampler = filterFunc(func(e *Entry) (bool, error) {
+			sampled = append(sampled, fmt.Sprintf("%s %s", e.Level, e.Message))
+			return false, nil
+		})
+		serve(httptest.NewRequest(http.MethodGet, "/ok", nil))
+		status = http.StatusBadRequest
+		serve(httptest.NewRequest(http.MethodGet, "/bad", nil))
+
+		Expect(sampled).To(Equal([]string{"info GET /ok 200"}))
+		entries := f.Entries()
+		Expect(entries).To(HaveLen(3))
+		Expect(entries[2].Message).To(Equal("GET /bad 400"))
+	})
+	It("should log summary of sampler not used by any backend", func() {
+		sampler := NewFilterSampling(0, 0, 0, 0)
+		defer sampler.Close()
+		h.Sampler = sampler
+		for i := 0; i < 4; i++ {
+			serve(httptest.NewRequest(http.MethodGet, "/ok", nil))
+		}
+		Expect(f.Entries()).To(HaveLen(4))
+
+		sampler.Summarize()
+		entries := f.Entries()
+		Expect(entries).To(HaveLen(5))
+		Expect(entries[4].Message).To(Equal(suppressedMessage))
+		Expect(value(entries[4], SuppressedProperty)).To(Equal(uint64(4)))
+	})
+	It("should record default status and support flushing", func() {
+		h.Handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.(http.Flusher).Flush()
+			Expect(http.NewResponseController(w).Flush()).To(Succeed())
+		})
+		w := serve(httptest.NewRequest(http.MethodGet, "/", nil))
+		Expect(w.Flushed).To(BeTrue())
+		Expect(value(f.Entries()[0], StatusProperty)).To(Equal(http.StatusOK))
+		Expect(value(f.Entries()[0], BytesProperty)).To(Equal(0))
+	})
+	It("should record final status after informational ones", func() {
+		h.Handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusEarlyHints)
+			w.WriteHeader(http.StatusNoContent)
+		})
+		serve(httptest.NewRequest(http.MethodGet, "/", nil))
+		Expect(value(f.Entries()[0], StatusProperty)).To(Equal(http.StatusNoContent))
+	})
+	It("should support hijacking connection", func() {
+		client, server := net.Pipe()
+		defer client.Close()
+		defer server.Close()
+		h.Handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			conn, _, err := http.NewResponseController(w).Hijack()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(conn).To(Equal(server))
+		})
+		h.ServeHTTP(&hijackRecorder{httptest.NewRecorder(), server},
+			httptest.NewRequest(http.MethodGet, "/ws", nil))
+		Expect(value(f.Entries()[0], StatusProperty)).To(Equal(http.StatusSwitchingProtocols))
+	})
+	It("should fail hijacking if wrapped writer does not support it", func() {
+		h.Handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _, err := w.(http.Hijacker).Hijack()
+			Expect(errors.Is(err, http.ErrNotSupported)).To(BeTrue())
+		})
+		serve(httptest.NewRequest(http.MethodGet, "/ws", nil))
+		Expect(value(f.Entries()[0], StatusProperty)).To(Equal(http.StatusOK))
+	})
+	It("should log request if handler panics", func() {
+		h.Handler = http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+			panic("boom")
+		})
+		Expect(func() {
+			serve(httptest.NewRequest(http.MethodGet, "/", nil))
+		}).To(PanicWith("boom"))
+		e := f.Entries()[0]
+		Expect(e.Level).To(Equal(ErrLevel))
+		Expect(value(e, StatusProperty)).To(Equal(http.StatusInternalServerError))
+		Expect(value(e, PanicProperty)).To(Equal("boom"))
+	})
+})
@@ -0,0 +1,377 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/SamsungSLAV/slav/logger/internal/caller"
+)
+
+const (
+	// URLProperty defines key of field holding URL of the outgoing HTTP request.
+	URLProperty = "url"
+	// AttemptProperty defines key of field holding number of attempt of sending the request.
+	AttemptProperty = "attempt"
+	// RequestHeadersProperty defines key of field holding captured headers of the request.
+	RequestHeadersProperty = "request_headers"
+	// ResponseHeadersProperty defines key of field holding captured headers of the response.
+	ResponseHeadersProperty = "response_headers"
+	// RequestBodyProperty defines key of field holding captured beginning of the request body.
+	RequestBodyProperty = "request_body"
+	// ResponseBodyProperty defines key of field holding captured beginning of the response body.
+	ResponseBodyProperty = "response_body"
+)
+
+// DefaultRedactHeaders contains headers, which values are masked by default by HTTPTransport.
+var DefaultRedactHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+}
+
+// httpPackage is the prefix of functions of net/http package, which are skipped while looking
+// for the caller of the request.
+const httpPackage = "net/http."
+
+// HTTPTransport is a http.RoundTripper logging outgoing requests and received responses
+// with their timing and status. Request ID carried by context of the request (see HTTPHandler)
+// is propagated in RequestIDHeader and TraceContext (see ContextWithTrace) in W3C traceparent
+// and tracestate headers, if Trace is set. Failed requests with idempotent methods are retried
+// up to Retries times, each attempt is logged with its number. Call context of entries is the first
+// function outside net/http package, i.e. the caller of http.Client's methods.
+type HTTPTransport struct {
+	// Transport sends requests. If nil, http.DefaultTransport is used.
+	Transport http.RoundTripper
+	// Logger logs requests.
+	Logger *Logger
+	// RequestIDHeader is the header carrying propagated request ID.
+	RequestIDHeader string
+	// Trace set to true makes trace context propagated.
+	Trace bool
+	// SuccessLevel is the level of requests with 1xx, 2xx and 3xx status codes.
+	SuccessLevel Level
+	// ClientErrorLevel is the level of requests with 4xx status codes.
+	ClientErrorLevel Level
+	// ServerErrorLevel is the level of requests with 5xx status codes.
+	ServerErrorLevel Level
+	// FailureLevel is the level of requests, which failed without response.
+	FailureLevel Level
+	// HeaderLimit limits length of captured header values. Headers are not captured if it is 0.
+	HeaderLimit int
+	// BodyLimit limits number of captured bytes of request and response bodies. Bodies are
+	// captured while they are read, so if it is set, the request is logged when BodyLimit bytes
+	// of the response body are read, reading fails or the body is closed. Bodies are not captured
+	// if it is 0 and bodies of 101 Switching Protocols responses are never captured.
+	BodyLimit int
+	// RedactHeaders contains headers, which captured values are replaced with RedactMask.
+	RedactHeaders []string
+	// RedactMask replaces values of RedactHeaders.
+	RedactMask string
+	// Retries is the maximum number of retries of requests with idempotent methods, which
+	// failed without response or with 5xx status code. Requests with body are retried only
+	// if the body can be obtained again (see http.Request's GetBody).
+	Retries int
+	// RetryDelay is the delay before every retry.
+	RetryDelay time.Duration
+}
+
+// NewHTTPTransport creates and returns a new HTTPTransport logging requests sent with t to l.
+// Successful requests are logged with InfoLevel, client errors with WarningLevel, and server
+// errors and failures with ErrLevel. Trace context is propagated. Headers and bodies are not
+// captured and requests are not retried.
+func NewHTTPTransport(l *Logger, t http.RoundTripper) *HTTPTransport {
+	return &HTTPTransport{
+		Transport:        t,
+		Logger:           l,
+		RequestIDHeader:  DefaultRequestIDHeader,
+		Trace:            true,
+		SuccessLevel:     InfoLevel,
+		ClientErrorLevel: WarningLevel,
+		ServerErrorLevel: ErrLevel,
+		FailureLevel:     ErrLevel,
+		RedactHeaders:    DefaultRedactHeaders,
+		RedactMask:       DefaultRedactMask,
+	}
+}
+
+// RoundTrip sends request, logs it and retries if needed. It implements http.RoundTripper
+// interface in HTTPTransport.
+func (t *HTTPTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	pc := caller.PC(httpPackage)
+	req = t.prepare(req)
+	for attempt := 1; ; attempt++ {
+		resp, err := t.send(req, attempt, pc)
+		if attempt > t.Retries || !retryable(req, resp, err) {
+			return resp, err
+		}
+		if resp != nil {
+			// Draining and closing the body also logs the attempt if the body is captured.
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+		if req, err = rewind(req); err != nil {
+			return nil, err
+		}
+		if err = sleep(req, t.RetryDelay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// send sends req once and logs it with call context of given program counter.
+func (t *HTTPTransport) send(req *http.Request, attempt int, pc uintptr) (*http.Response, error) {
+	e := t.Logger.WithContext(req.Context()).WithCaller(pc).WithFields(
+		String(MethodProperty, req.Method),
+		String(URLProperty, req.URL.Redacted()),
+		Int(AttemptProperty, attempt),
+	)
+	reqBody := t.captureRequest(e, req)
+	start := time.Now()
+	resp, err := t.transport().RoundTrip(req)
+	e.WithFields(Duration(DurationProperty, time.Since(start)))
+	if reqBody != nil {
+		e.WithFields(String(RequestBodyProperty, reqBody.captured()))
+	}
+	msg := req.Method + " " + req.URL.Redacted()
+	if err != nil {
+		e.WithError(err).Log(t.FailureLevel, msg+" failed")
+		return nil, err
+	}
+	e.WithFields(Int(StatusProperty, resp.StatusCode))
+	t.captureResponse(e, resp, fmt.Sprintf("%s %d", msg, resp.StatusCode))
+	return resp, nil
+}
+
+// prepare returns a copy of req with request ID and trace context carried by its context set
+// in headers, unless the headers are already set.
+func (t *HTTPTransport) prepare(req *http.Request) *http.Request {
+	req = req.Clone(req.Context())
+	id, ok := ContextProperties(req.Context())[RequestIDProperty].(string)
+	if ok && t.RequestIDHeader != "" && req.Header.Get(t.RequestIDHeader) == "" {
+		req.Header.Set(t.RequestIDHeader, id)
+	}
+	tc, ok := TraceFromContext(req.Context())
+	if ok && t.Trace && req.Header.Get(TraceparentHeader) == "" {
+		SetTraceHTTPHeader(req.Header, tc)
+	}
+	return req
+}
+
+// captureRequest adds headers of req to e and wraps its body to capture the beginning of it.
+// It returns the wrapping capture or nil if the body is not captured.
+func (t *HTTPTransport) captureRequest(e *Entry, req *http.Request) *bodyCapture {
+	if t.HeaderLimit > 0 {
+		e.WithFields(Any(RequestHeadersProperty, t.headers(req.Header)))
+	}
+	if t.BodyLimit <= 0 || req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+	body := newBodyCapture(req.Body, t.BodyLimit, nil)
+	req.Body = body
+	return body
+}
+
+// captureResponse adds headers of resp to e and logs e with msg. If the body of resp is
+// captured, e is logged when the capture is done.
+func (t *HTTPTransport) captureResponse(e *Entry, resp *http.Response, msg string) {
+	level := t.level(resp.StatusCode)
+	if t.HeaderLimit > 0 {
+		e.WithFields(Any(ResponseHeadersProperty, t.headers(resp.Header)))
+	}
+	// Body of 101 Switching Protocols response is also an io.Writer, which must not be hidden.
+	if t.BodyLimit <= 0 || resp.Body == nil || resp.Body == http.NoBody ||
+		resp.StatusCode == http.StatusSwitchingProtocols {
+		e.Log(level, msg)
+		return
+	}
+	resp.Body = newBodyCapture(resp.Body, t.BodyLimit, func(captured string) {
+		e.WithFields(String(ResponseBodyProperty, captured)).Log(level, msg)
+	})
+}
+
+// headers returns captured headers with values limited to HeaderLimit and RedactHeaders masked.
+func (t *HTTPTransport) headers(h http.Header) Properties {
+	props := make(Properties, len(h))
+	for name, values := range h {
+		value := strings.Join(values, ", ")
+		if len(value) > t.HeaderLimit {
+			value = value[:runeBoundary(value, t.HeaderLimit)]
+		}
+		props[name] = value
+	}
+	for _, name := range t.RedactHeaders {
+		name = http.CanonicalHeaderKey(name)
+		if _, ok := props[name]; ok {
+			props[name] = t.RedactMask
+		}
+	}
+	return props
+}
+
+// level returns level of request with given status code.
+func (t *HTTPTransport) level(status int) Level {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return t.ServerErrorLevel
+	case status >= http.StatusBadRequest:
+		return t.ClientErrorLevel
+	}
+	return t.SuccessLevel
+}
+
+// transport returns Transport or http.DefaultTransport if it is not set.
+func (t *HTTPTransport) transport() http.RoundTripper {
+	if t.Transport != nil {
+		return t.Transport
+	}
+	return http.DefaultTransport
+}
+
+// HTTPTransport returns a new HTTPTransport logging requests sent with t.
+func (l *Logger) HTTPTransport(t http.RoundTripper) *HTTPTransport {
+	return NewHTTPTransport(l, t)
+}
+
+// idempotentMethods contains HTTP methods of requests, which can be retried.
+var idempotentMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+	http.MethodTrace:   true,
+	http.MethodPut:     true,
+	http.MethodDelete:  true,
+}
+
+// retryable returns true if req, which failed with err or got resp, can be retried.
+func retryable(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil || !idempotentMethods[req.Method] {
+		return false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	return err != nil || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// rewind returns a copy of req with a new body obtained with GetBody.
+func rewind(req *http.Request) (*http.Request, error) {
+	req = req.Clone(req.Context())
+	if req.GetBody == nil {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = body
+	return req, nil
+}
+
+// sleep waits for given time or until context of req is done.
+func sleep(req *http.Request, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-req.Context().Done():
+		return req.Context().Err()
+	}
+}
+
+// runeBoundary returns the greatest index not greater than n, at which a UTF-8 encoded
+// character of s starts.
+func runeBoundary(s string, n int) int {
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return n
+}
+
+// bodyCapture is an io.ReadCloser copying at most limit bytes of the wrapped body while they
+// are read. It calls done once with the captured bytes when the limit is reached, reading fails
+// (e.g. at EOF) or the body is closed.
+type bodyCapture struct {
+	io.ReadCloser
+	limit    int
+	done     func(captured string)
+	mutex    sync.Mutex
+	buf      []byte
+	finished sync.Once
+}
+
+// newBodyCapture wraps body capturing at most limit bytes of it. done may be nil.
+func newBodyCapture(body io.ReadCloser, limit int, done func(string)) *bodyCapture {
+	return &bodyCapture{
+		ReadCloser: body,
+		limit:      limit,
+		done:       done,
+	}
+}
+
+// Read reads from the wrapped body and captures read bytes. It implements io.Reader interface
+// in bodyCapture.
+func (b *bodyCapture) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.mutex.Lock()
+	if room := b.limit - len(b.buf); room > 0 {
+		if room > n {
+			room = n
+		}
+		b.buf = append(b.buf, p[:room]...)
+	}
+	full := len(b.buf) >= b.limit
+	b.mutex.Unlock()
+	if full || err != nil {
+		b.finish()
+	}
+	return n, err
+}
+
+// Close closes the wrapped body. It implements io.Closer interface in bodyCapture.
+func (b *bodyCapture) Close() error {
+	err := b.ReadCloser.Close()
+	b.finish()
+	return err
+}
+
+// captured returns bytes captured so far.
+func (b *bodyCapture) captured() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return string(b.buf)
+}
+
+// finish calls done with captured bytes unless it has been already called.
+func (b *bodyCapture) finish() {
+	b.finished.Do(func() {
+		if b.done != nil {
+			b.done(b.captured())
+		}
+	})
+}
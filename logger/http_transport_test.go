@@ -0,0 +1,354 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	T "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+// roundTripperFunc is a http.RoundTripper calling itself to send requests.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+var _ = Describe("HTTPTransport", func() {
+	const thisFile = "http_transport_test.go"
+	var (
+		L        *Logger
+		f        *filterCollector
+		server   *httptest.Server
+		statuses []int
+		requests int32
+		received *http.Request
+		t        *HTTPTransport
+		client   *http.Client
+	)
+
+	BeforeEach(func() {
+		L = NewLogger()
+		L.SetThreshold(DebugLevel)
+		f = new(filterCollector)
+		L.AddBackend("collector", Backend{
+			Filter:     f,
+			Serializer: NewSerializerJSON(),
+			Writer:     new(writerCollector),
+		})
+		statuses = nil
+		requests = 0
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := atomic.AddInt32(&requests, 1)
+			received = r
+			body, _ := io.ReadAll(r.Body)
+			w.Header().Set("Set-Cookie", "session=secret")
+			w.Header().Set("X-Long", "0123456789")
+			if int(n) <= len(statuses) {
+				w.WriteHeader(statuses[n-1])
+			}
+			_, _ = w.Write(append([]byte("echo "), body...))
+		}))
+		t = L.HTTPTransport(nil)
+		client = &http.Client{Transport: t}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	value := func(e *Entry, key string) interface{} {
+		v, _ := e.property(key)
+		return v
+	}
+
+	It("should create a new object with default configuration", func() {
+		Expect(t.Transport).To(BeNil())
+		Expect(t.Logger).To(Equal(L))
+		Expect(t.RequestIDHeader).To(Equal(DefaultRequestIDHeader))
+		Expect(t.Trace).To(BeTrue())
+		Expect(t.SuccessLevel).To(Equal(InfoLevel))
+		Expect(t.ClientErrorLevel).To(Equal(WarningLevel))
+		Expect(t.ServerErrorLevel).To(Equal(ErrLevel))
+		Expect(t.FailureLevel).To(Equal(ErrLevel))
+		Expect(t.HeaderLimit).To(BeZero())
+		Expect(t.BodyLimit).To(BeZero())
+		Expect(t.RedactHeaders).To(Equal(DefaultRedactHeaders))
+		Expect(t.RedactMask).To(Equal(DefaultRedactMask))
+		Expect(t.Retries).To(BeZero())
+	})
+	It("should log request and response", func() {
+		_, _, line, _ := runtime.Caller(0)
+		resp, err := client.Get(server.URL + "/path?q=1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Body.Close()).To(Succeed())
+
+		entries := f.Entries()
+		Expect(entries).To(HaveLen(1))
+		e := entries[0]
+		Expect(e.Level).To(Equal(InfoLevel))
+		Expect(e.Message).To(Equal("GET " + server.URL + "/path?q=1 200"))
+		Expect(value(e, MethodProperty)).To(Equal("GET"))
+		Expect(value(e, URLProperty)).To(Equal(server.URL + "/path?q=1"))
+		Expect(value(e, StatusProperty)).To(Equal(http.StatusOK))
+		Expect(value(e, AttemptProperty)).To(Equal(1))
+		Expect(value(e, DurationProperty)).To(BeNumerically(">", 0))
+		Expect(e.Fields).To(HaveLen(5))
+		Expect(e.CallContext.File).To(Equal(thisFile))
+		Expect(e.CallContext.Line).To(Equal(line + 1))
+	})
+	It("should propagate request ID from context", func() {
+		ctx := ContextWithProperties(context.Background(), Properties{RequestIDProperty: "abc"})
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		Expect(err).NotTo(HaveOccurred())
+		resp, err := client.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Body.Close()).To(Succeed())
+
+		Expect(received.Header.Get(DefaultRequestIDHeader)).To(Equal("abc"))
+		Expect(req.Header.Get(DefaultRequestIDHeader)).To(BeEmpty())
+		Expect(f.Entries()[0].Properties).To(Equal(Properties{RequestIDProperty: "abc"}))
+	})
+	It("should propagate trace context", func() {
+		tc := NewTraceContext()
+		tc.State = "a=1"
+		req, err := http.NewRequestWithContext(ContextWithTrace(context.Background(), tc),
+			http.MethodGet, server.URL, nil)
+		Expect(err).NotTo(HaveOccurred())
+		resp, err := client.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Body.Close()).To(Succeed())
+
+		Expect(received.Header.Get(TraceparentHeader)).To(Equal(tc.Traceparent()))
+		Expect(received.Header.Get(TracestateHeader)).To(Equal("a=1"))
+		Expect(f.Entries()[0].Properties).To(Equal(Properties{
+			TraceIDProperty: tc.TraceID,
+			SpanIDProperty:  tc.SpanID,
+		}))
+	})
+	It("should not propagate trace context if disabled", func() {
+		t.Trace = false
+		ctx := ContextWithTrace(context.Background(), NewTraceContext())
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		Expect(err).NotTo(HaveOccurred())
+		resp, err := client.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Body.Close()).To(Succeed())
+		Expect(received.Header.Get(TraceparentHeader)).To(BeEmpty())
+	})
+	It("should capture limited and redacted headers and bodies", func() {
+		t.HeaderLimit = 5
+		t.BodyLimit = 7
+		req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("request body"))
+		Expect(err).NotTo(HaveOccurred())
+		req.Header.Set("Authorization", "Bearer secret")
+		req.Header.Set("X-Job", "1")
+		resp, err := client.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Body.Close()).To(Succeed())
+		Expect(string(body)).To(Equal("echo request body"))
+
+		e := f.Entries()[0]
+		Expect(value(e, RequestHeadersProperty)).To(Equal(Properties{
+			"Authorization": DefaultRedactMask,
+			"X-Job":         "1",
+		}))
+		Expect(value(e, ResponseHeadersProperty)).To(SatisfyAll(
+			HaveKeyWithValue("Set-Cookie", DefaultRedactMask),
+			HaveKeyWithValue("X-Long", "01234"),
+		))
+		Expect(value(e, RequestBodyProperty)).To(Equal("request"))
+		Expect(value(e, ResponseBodyProperty)).To(Equal("echo re"))
+	})
+	T.DescribeTable("should choose level by status class",
+		func(code int, expected Level) {
+			statuses = []int{code}
+			resp, err := client.Get(server.URL)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Body.Close()).To(Succeed())
+			Expect(f.Entries()[0].Level).To(Equal(expected))
+		},
+		T.Entry("success", http.StatusAccepted, InfoLevel),
+		T.Entry("client error", http.StatusNotFound, WarningLevel),
+		T.Entry("server error", http.StatusBadGateway, ErrLevel),
+	)
+	It("should log failure of the request", func() {
+		testError := errors.New("test error")
+		t.Transport = roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			return nil, testError
+		})
+		_, err := client.Get("http://localhost/")
+		Expect(err).To(HaveOccurred())
+
+		e := f.Entries()[0]
+		Expect(e.Level).To(Equal(ErrLevel))
+		Expect(e.Message).To(Equal("GET http://localhost/ failed"))
+		Expect(e.Properties).To(Equal(Properties{ErrorProperty: NewErrorValue(testError)}))
+	})
+	It("should retry idempotent requests logging every attempt", func() {
+		t.Retries = 3
+		t.RetryDelay = time.Millisecond
+		t.BodyLimit = 100
+		statuses = []int{http.StatusServiceUnavailable, http.StatusInternalServerError}
+		req, err := http.NewRequest(http.MethodPut, server.URL, strings.NewReader("data"))
+		Expect(err).NotTo(HaveOccurred())
+		resp, err := client.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Entries()).To(HaveLen(2))
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Body.Close()).To(Succeed())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(string(body)).To(Equal("echo data"))
+
+		entries := f.Entries()
+		Expect(entries).To(HaveLen(3))
+		for i, e := range entries {
+			Expect(value(e, AttemptProperty)).To(Equal(i + 1))
+			Expect(value(e, RequestBodyProperty)).To(Equal("data"))
+			Expect(value(e, ResponseBodyProperty)).To(Equal("echo data"))
+		}
+		Expect(value(entries[0], StatusProperty)).To(Equal(http.StatusServiceUnavailable))
+		Expect(value(entries[1], StatusProperty)).To(Equal(http.StatusInternalServerError))
+		Expect(value(entries[2], StatusProperty)).To(Equal(http.StatusOK))
+	})
+	It("should stop retrying after Retries attempts", func() {
+		t.Retries = 1
+		statuses = []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway}
+		resp, err := client.Get(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Body.Close()).To(Succeed())
+		Expect(resp.StatusCode).To(Equal(http.StatusBadGateway))
+		Expect(f.Entries()).To(HaveLen(2))
+	})
+	It("should not retry non-idempotent requests", func() {
+		t.Retries = 3
+		statuses = []int{http.StatusBadGateway}
+		resp, err := client.Post(server.URL, "text/plain", strings.NewReader("data"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Body.Close()).To(Succeed())
+		Expect(f.Entries()).To(HaveLen(1))
+	})
+	It("should stop retrying when context is done", func() {
+		t.Retries = 3
+		t.RetryDelay = time.Hour
+		statuses = []int{http.StatusBadGateway}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.Do(req)
+		Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+		Expect(f.Entries()).To(HaveLen(1))
+	})
+	It("should not split characters of limited header values", func() {
+		t.HeaderLimit = 5
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		Expect(err).NotTo(HaveOccurred())
+		req.Header.Set("X-Name", "zażółć")
+		resp, err := client.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Body.Close()).To(Succeed())
+		Expect(value(f.Entries()[0], RequestHeadersProperty)).To(
+			HaveKeyWithValue("X-Name", "zaż"))
+	})
+	It("should capture body of streamed response while it is read", func() {
+		t.BodyLimit = 4
+		release := make(chan struct{})
+		defer close(release)
+		server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("ab"))
+			w.(http.Flusher).Flush()
+			<-release
+		})
+		resp, err := client.Get(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Entries()).To(BeEmpty())
+
+		buf := make([]byte, 2)
+		_, err = io.ReadFull(resp.Body, buf)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Entries()).To(BeEmpty())
+		Expect(resp.Body.Close()).To(Succeed())
+		Expect(resp.Body.Close()).To(Succeed())
+
+		entries := f.Entries()
+		Expect(entries).To(HaveLen(1))
+		Expect(value(entries[0], ResponseBodyProperty)).To(Equal("ab"))
+	})
+	It("should log request when limit of captured response body is reached", func() {
+		t.BodyLimit = 3
+		resp, err := client.Get(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+		buf := make([]byte, 5)
+		_, err = io.ReadFull(resp.Body, buf)
+		Expect(err).NotTo(HaveOccurred())
+
+		entries := f.Entries()
+		Expect(entries).To(HaveLen(1))
+		Expect(value(entries[0], ResponseBodyProperty)).To(Equal("ech"))
+		Expect(resp.Body.Close()).To(Succeed())
+		Expect(f.Entries()).To(HaveLen(1))
+	})
+	It("should not wrap body of switching protocols response", func() {
+		t.BodyLimit = 10
+		conn := &nopReadWriteCloser{Reader: strings.NewReader("data")}
+		t.Transport = roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusSwitchingProtocols,
+				Header:     http.Header{},
+				Body:       conn,
+			}, nil
+		})
+		resp, err := t.RoundTrip(httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Body).To(BeIdenticalTo(conn))
+
+		entries := f.Entries()
+		Expect(entries).To(HaveLen(1))
+		Expect(value(entries[0], StatusProperty)).To(Equal(http.StatusSwitchingProtocols))
+		Expect(value(entries[0], ResponseBodyProperty)).To(BeNil())
+	})
+})
+
+// nopReadWriteCloser is an io.ReadWriteCloser discarding written data and ignoring Close.
+type nopReadWriteCloser struct {
+	io.Reader
+}
+
+// Write discards p.
+func (nopReadWriteCloser) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+// Close does nothing.
+func (nopReadWriteCloser) Close() error {
+	return nil
+}
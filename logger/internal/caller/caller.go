@@ -0,0 +1,66 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+// Package caller finds call sites of logging calls made through other logging libraries.
+package caller
+
+import (
+	"runtime"
+	"strings"
+)
+
+// maxDepth limits number of inspected stack frames.
+const maxDepth = 32
+
+// PC returns program counter of the first function above the caller of PC, which does not belong
+// to any of packages with given path prefixes (e.g. the logging library). The program counter
+// is a return address like the ones returned by runtime.Callers. If there is no such function,
+// 0 is returned.
+func PC(prefixes ...string) uintptr {
+	var pcs [maxDepth]uintptr
+	n := runtime.Callers(3, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !hasPrefix(frame.Function, prefixes) {
+			return FramePC(frame)
+		}
+		if !more {
+			return 0
+		}
+	}
+}
+
+// hasPrefix returns true if s has any of given prefixes.
+func hasPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// FramePC converts program counter of frame to return address expected by Entry.WithCaller.
+func FramePC(frame runtime.Frame) uintptr {
+	return ReturnPC(frame.PC)
+}
+
+// ReturnPC converts program counter of a call instruction (like runtime.Frame.PC) to return
+// address expected by Entry.WithCaller.
+func ReturnPC(pc uintptr) uintptr {
+	return pc + 1
+}
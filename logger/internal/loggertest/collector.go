@@ -0,0 +1,54 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+// Package loggertest provides helpers shared by tests of the adapter packages.
+package loggertest
+
+import (
+	"sync"
+
+	"github.com/SamsungSLAV/slav/logger"
+	. "github.com/onsi/gomega"
+)
+
+// Collector is a logger.Filter collecting copies of entries and rejecting them.
+type Collector struct {
+	mutex   sync.Mutex
+	entries []logger.Entry
+}
+
+// Verify stores copy of the entry and rejects it.
+func (c *Collector) Verify(entry *logger.Entry) (bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.entries = append(c.entries, *entry.Clone())
+	return false, nil
+}
+
+// Last returns the most recently collected entry. It fails the test if no entry was collected.
+func (c *Collector) Last() logger.Entry {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	ExpectWithOffset(1, c.entries).NotTo(BeEmpty())
+	return c.entries[len(c.entries)-1]
+}
+
+// Count returns number of collected entries.
+func (c *Collector) Count() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.entries)
+}
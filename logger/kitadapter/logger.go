@@ -0,0 +1,98 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+// Package kitadapter provides go-kit log.Logger writing key-value pairs into SLAV logger.
+package kitadapter
+
+import (
+	"fmt"
+
+	"github.com/SamsungSLAV/slav/logger"
+	"github.com/SamsungSLAV/slav/logger/internal/caller"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+)
+
+// kitPackage is the import path prefix of go-kit. Its frames are skipped while looking
+// for the caller.
+const kitPackage = "github.com/go-kit/"
+
+// DefaultMessageKey is the default key of the value used as message of the log entry.
+const DefaultMessageKey = "msg"
+
+// Level converts go-kit level value to logger.Level.
+func Level(v level.Value) logger.Level {
+	switch v {
+	case level.ErrorValue():
+		return logger.ErrLevel
+	case level.WarnValue():
+		return logger.WarningLevel
+	case level.InfoValue():
+		return logger.InfoLevel
+	}
+	return logger.DebugLevel
+}
+
+// Logger is a go-kit log.Logger logging key-value pairs with logger.Logger. Level of entry
+// is taken from go-kit level value (see package github.com/go-kit/log/level), message from
+// value of MessageKey and remaining pairs are logged as properties, with errors converted
+// to logger.ErrorValue. The first function outside of go-kit is used as call context.
+type Logger struct {
+	// Logger logs entries.
+	Logger *logger.Logger
+	// Level is used for key-value pairs without level value.
+	Level logger.Level
+	// MessageKey is the key of message value.
+	MessageKey string
+}
+
+// NewLogger creates and returns a new Logger logging entries with l. Entries without
+// level are logged with InfoLevel.
+func NewLogger(l *logger.Logger) *Logger {
+	return &Logger{
+		Logger:     l,
+		Level:      logger.InfoLevel,
+		MessageKey: DefaultMessageKey,
+	}
+}
+
+// Log logs key-value pairs. Missing value of the last key is replaced with
+// log.ErrMissingValue. It implements log.Logger interface in Logger.
+func (l *Logger) Log(keyvals ...interface{}) error {
+	if len(keyvals)%2 != 0 {
+		keyvals = append(keyvals, log.ErrMissingValue)
+	}
+	lvl := l.Level
+	msg := ""
+	props := make(logger.Properties, len(keyvals)/2)
+	for i := 0; i < len(keyvals); i += 2 {
+		key := fmt.Sprint(keyvals[i])
+		switch v := keyvals[i+1].(type) {
+		case level.Value:
+			lvl = Level(v)
+		case error:
+			props[key] = logger.NewErrorValue(v)
+		default:
+			if key == l.MessageKey {
+				msg = fmt.Sprint(v)
+				continue
+			}
+			props[key] = v
+		}
+	}
+	l.Logger.WithProperties(props).WithCaller(caller.PC(kitPackage)).Log(lvl, msg)
+	return nil
+}
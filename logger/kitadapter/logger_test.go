@@ -0,0 +1,109 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package kitadapter
+
+import (
+	"errors"
+	"runtime"
+
+	"github.com/SamsungSLAV/slav/logger"
+	"github.com/SamsungSLAV/slav/logger/internal/loggertest"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	. "github.com/onsi/ginkgo"
+	T "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+const thisFile = "logger_test.go"
+
+var _ = Describe("Logger", func() {
+	var (
+		L *logger.Logger
+		c *loggertest.Collector
+		k *Logger
+	)
+
+	BeforeEach(func() {
+		L = logger.NewLogger()
+		Expect(L.SetThreshold(logger.DebugLevel)).To(Succeed())
+		c = new(loggertest.Collector)
+		L.AddBackend("collector", logger.Backend{
+			Filter:     c,
+			Serializer: logger.NewSerializerJSON(),
+			Writer:     logger.NewWriterStderr(),
+		})
+		k = NewLogger(L)
+	})
+
+	It("should create logger with default configuration", func() {
+		Expect(k.Logger).To(Equal(L))
+		Expect(k.Level).To(Equal(logger.InfoLevel))
+		Expect(k.MessageKey).To(Equal(DefaultMessageKey))
+	})
+	It("should log key-value pairs as properties", func() {
+		testError := errors.New("test error")
+		Expect(level.Warn(log.With(k, "name", "alice")).
+			Log("msg", "message", "count", 3, "err", testError)).To(Succeed())
+		e := c.Last()
+		Expect(e.Level).To(Equal(logger.WarningLevel))
+		Expect(e.Message).To(Equal("message"))
+		Expect(e.Properties).To(Equal(logger.Properties{
+			"name":  "alice",
+			"count": 3,
+			"err":   logger.NewErrorValue(testError),
+		}))
+	})
+	It("should use default level and message key", func() {
+		k.Level = logger.NoticeLevel
+		k.MessageKey = "message"
+		Expect(k.Log("message", "text", "msg", "value")).To(Succeed())
+		e := c.Last()
+		Expect(e.Level).To(Equal(logger.NoticeLevel))
+		Expect(e.Message).To(Equal("text"))
+		Expect(e.Properties).To(Equal(logger.Properties{"msg": "value"}))
+	})
+	It("should substitute missing value", func() {
+		Expect(k.Log("key")).To(Succeed())
+		Expect(c.Last().Properties).To(Equal(logger.Properties{
+			"key": logger.NewErrorValue(log.ErrMissingValue),
+		}))
+	})
+	It("should find caller outside of go-kit", func() {
+		_, _, line, _ := runtime.Caller(0)
+		_ = level.Info(log.With(k, "a", 1)).Log("msg", "message")
+		e := c.Last()
+		Expect(e.CallContext.File).To(Equal(thisFile))
+		Expect(e.CallContext.Line).To(Equal(line + 1))
+	})
+	It("should use direct caller", func() {
+		_, _, line, _ := runtime.Caller(0)
+		_ = k.Log("msg", "message")
+		e := c.Last()
+		Expect(e.CallContext.File).To(Equal(thisFile))
+		Expect(e.CallContext.Line).To(Equal(line + 1))
+	})
+	T.DescribeTable("should map levels",
+		func(value level.Value, expected logger.Level) {
+			Expect(Level(value)).To(Equal(expected))
+		},
+		T.Entry("error", level.ErrorValue(), logger.ErrLevel),
+		T.Entry("warning", level.WarnValue(), logger.WarningLevel),
+		T.Entry("info", level.InfoValue(), logger.InfoLevel),
+		T.Entry("debug", level.DebugValue(), logger.DebugLevel),
+	)
+})
@@ -0,0 +1,106 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"fmt"
+	"sync"
+)
+
+// maxLazyDepth limits number of evaluations of lazy values returning other lazy values.
+const maxLazyDepth = 8
+
+// LogValuer is implemented by property and field values which are expensive to compute.
+// LogValue is called only if entry is written by at least one backend and only once
+// for all backends.
+type LogValuer interface {
+	// LogValue returns value to be logged.
+	LogValue() interface{}
+}
+
+// LazyValue is a function computing property or field value only if entry is written.
+// Plain func() interface{} values are treated the same way.
+type LazyValue func() interface{}
+
+// LogValue calls f. It implements LogValuer interface in LazyValue type.
+func (f LazyValue) LogValue() interface{} {
+	return f()
+}
+
+// Lazy creates a field with value computed by f only if entry is written.
+func Lazy(key string, f func() interface{}) Field {
+	return Field{Key: key, Type: FieldTypeAny, Interface: LazyValue(f)}
+}
+
+// LazyPanicError describes panic which occurred during evaluation of a lazy value. It replaces
+// the value in logged entry.
+type LazyPanicError struct {
+	// Value is the value passed to panic.
+	Value interface{}
+}
+
+// Error returns description of the panic. It implements error interface in LazyPanicError.
+func (e *LazyPanicError) Error() string {
+	return fmt.Sprintf("lazy value panicked: %v", e.Value)
+}
+
+// Unwrap returns panic value if it is an error.
+func (e *LazyPanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// lazyState is shared by entry and its clones. It holds entry with lazy values evaluated.
+type lazyState struct {
+	once  sync.Once
+	entry *Entry
+}
+
+// logValuer returns LogValuer of v if it is a lazy value.
+func logValuer(v interface{}) (LogValuer, bool) {
+	switch f := v.(type) {
+	case LogValuer:
+		return f, true
+	case func() interface{}:
+		return LazyValue(f), true
+	}
+	return nil, false
+}
+
+// isLazy returns true if v is a lazy value.
+func isLazy(v interface{}) bool {
+	_, ok := logValuer(v)
+	return ok
+}
+
+// evaluate returns value of v, evaluating it if v is a lazy value. Panic during evaluation
+// is returned as LazyPanicError.
+func evaluate(v interface{}) (value interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			value, err = nil, &LazyPanicError{Value: r}
+		}
+	}()
+	for i := 0; i < maxLazyDepth; i++ {
+		lv, ok := logValuer(v)
+		if !ok {
+			break
+		}
+		v = lv.LogValue()
+	}
+	return v, nil
+}
@@ -0,0 +1,140 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"errors"
+	"sync/atomic"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// logValuerFunc is a LogValuer calling itself.
+type logValuerFunc func() interface{}
+
+func (f logValuerFunc) LogValue() interface{} {
+	return f()
+}
+
+var _ = Describe("Lazy values", func() {
+	var (
+		L     *Logger
+		w     *writerCollector
+		calls int32
+		pass  int32
+	)
+	expensive := func() interface{} {
+		atomic.AddInt32(&calls, 1)
+		return "computed"
+	}
+
+	BeforeEach(func() {
+		L = NewLogger()
+		w = new(writerCollector)
+		calls = 0
+		pass = 1
+		filter := filterFunc(func(*Entry) (bool, error) {
+			return atomic.LoadInt32(&pass) != 0, nil
+		})
+		for _, name := range []string{"first", "second"} {
+			L.AddBackend(name, Backend{
+				Filter:     filter,
+				Serializer: newPlainSerializerText(),
+				Writer:     w,
+			})
+		}
+	})
+
+	It("should evaluate functions, LazyValue and LogValuer once for all backends", func() {
+		L.WithProperties(Properties{
+			"func":     expensive,
+			"lazy":     LazyValue(expensive),
+			"valuer":   logValuerFunc(expensive),
+			"constant": 7,
+		}).WithFields(Lazy("field", expensive)).Info("message")
+		Expect(atomic.LoadInt32(&calls)).To(Equal(int32(4)))
+		expected := "[INF] message {field:computed;constant:7;func:computed;lazy:computed;" +
+			"valuer:computed;}"
+		Expect(w.Messages()).To(Equal([]string{expected, expected}))
+	})
+	It("should evaluate values in parallel mode once", func() {
+		L.SetParallel(true)
+		L.WithProperty("func", expensive).Info("message")
+		Expect(atomic.LoadInt32(&calls)).To(Equal(int32(1)))
+		Expect(w.Messages()).To(HaveLen(2))
+	})
+	It("should not evaluate values below threshold", func() {
+		L.SetThreshold(InfoLevel)
+		L.WithProperty("func", expensive).Debug("message")
+		Expect(atomic.LoadInt32(&calls)).To(BeZero())
+		Expect(w.Messages()).To(BeEmpty())
+	})
+	It("should not evaluate values of filtered entries", func() {
+		atomic.StoreInt32(&pass, 0)
+		L.WithFields(Lazy("field", expensive)).Info("message")
+		Expect(atomic.LoadInt32(&calls)).To(BeZero())
+		Expect(w.Messages()).To(BeEmpty())
+	})
+	It("should evaluate values again when entry is logged again", func() {
+		e := L.WithProperty("func", expensive)
+		e.Info("message")
+		e.Info("message")
+		Expect(atomic.LoadInt32(&calls)).To(Equal(int32(2)))
+	})
+	It("should evaluate lazy values returned by lazy values", func() {
+		L.WithProperty("func", LazyValue(func() interface{} {
+			return logValuerFunc(expensive)
+		})).Info("message")
+		Expect(w.Messages()[0]).To(Equal("[INF] message {func:computed;}"))
+	})
+	It("should not modify logged entry", func() {
+		e := L.WithProperty("func", expensive)
+		e.Info("message")
+		Expect(e.Properties["func"]).NotTo(Equal("computed"))
+	})
+	It("should log panics as errors", func() {
+		testError := errors.New("test error")
+		L.WithProperty("property", func() interface{} {
+			panic("oops")
+		}).WithFields(Lazy("field", func() interface{} {
+			panic(testError)
+		})).Info("message")
+		Expect(w.Messages()[0]).To(Equal(`[INF] message {field:"lazy value panicked: ` +
+			`test error";property:"lazy value panicked: oops";}`))
+	})
+	Describe("LazyPanicError", func() {
+		It("should unwrap panic value if it is an error", func() {
+			testError := errors.New("test error")
+			Expect(errors.Unwrap(&LazyPanicError{Value: testError})).To(Equal(testError))
+			Expect(errors.Unwrap(&LazyPanicError{Value: "oops"})).To(BeNil())
+		})
+	})
+	Describe("resolved", func() {
+		It("should return the same entry if it has no lazy values", func() {
+			e := L.WithProperty("constant", 7)
+			Expect(e.resolved()).To(BeIdenticalTo(e))
+		})
+		It("should share evaluated values with clones", func() {
+			e := L.WithProperty("func", expensive)
+			c := e.clone()
+			Expect(e.resolved()).To(BeIdenticalTo(c.resolved()))
+			Expect(c.resolved().Properties).To(HaveKeyWithValue("func", "computed"))
+			Expect(atomic.LoadInt32(&calls)).To(Equal(int32(1)))
+		})
+	})
+})
@@ -0,0 +1,236 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// DefaultMaxLineLength is the default maximum length of lines logged by LineWriter.
+const DefaultMaxLineLength = 64 * 1024
+
+// levelPrefixes maps level names used in prefixes of lines written to LineWriter to levels.
+var levelPrefixes = map[string]Level{
+	"emerg":     EmergLevel,
+	"emergency": EmergLevel,
+	"panic":     EmergLevel,
+	"alert":     AlertLevel,
+	"crit":      CritLevel,
+	"critical":  CritLevel,
+	"fatal":     CritLevel,
+	"err":       ErrLevel,
+	"error":     ErrLevel,
+	"war":       WarningLevel,
+	"warn":      WarningLevel,
+	"warning":   WarningLevel,
+	"not":       NoticeLevel,
+	"notice":    NoticeLevel,
+	"inf":       InfoLevel,
+	"info":      InfoLevel,
+	"deb":       DebugLevel,
+	"debug":     DebugLevel,
+	"trace":     DebugLevel,
+}
+
+// maxLevelPrefixLength limits length of level names searched in "NAME:" prefixes.
+const maxLevelPrefixLength = len("emergency")
+
+// lineBuffer splits written data into lines of limited length.
+type lineBuffer struct {
+	// buf holds incomplete line.
+	buf []byte
+}
+
+// write appends p to the buffer and calls emit for every complete line without the end of line
+// character. If a line gets longer than maxLength bytes (or DefaultMaxLineLength if maxLength is
+// not positive), its first bytes up to the limit are emitted without waiting for the end of it,
+// so the buffer never holds more than maxLength bytes between calls. Multi-byte UTF-8 characters
+// are not split, if possible.
+func (b *lineBuffer) write(p []byte, maxLength int, emit func(line string)) {
+	if maxLength <= 0 {
+		maxLength = DefaultMaxLineLength
+	}
+	b.buf = append(b.buf, p...)
+	for {
+		i := bytes.IndexByte(b.buf, '\n')
+		if i >= 0 && i <= maxLength {
+			emit(string(b.buf[:i]))
+			b.buf = b.buf[i+1:]
+			continue
+		}
+		if len(b.buf) <= maxLength {
+			break
+		}
+		n := maxLength
+		for n > 0 && !utf8.RuneStart(b.buf[n]) {
+			n--
+		}
+		if n == 0 {
+			n = maxLength
+		}
+		emit(string(b.buf[:n]))
+		b.buf = b.buf[n:]
+	}
+	if len(b.buf) == 0 {
+		b.buf = nil
+	}
+}
+
+// flush calls emit with incomplete line, if there is any.
+func (b *lineBuffer) flush(emit func(line string)) {
+	if len(b.buf) > 0 {
+		emit(string(b.buf))
+		b.buf = nil
+	}
+}
+
+// LineWriter is an io.Writer logging every written line as a separate entry. It bridges
+// libraries writing to io.Writer or using standard log package to Logger.
+type LineWriter struct {
+	// Logger logs the lines.
+	Logger *Logger
+	// Level is the level of logged lines.
+	Level Level
+	// ParseLevel set to true makes level prefixes of lines (e.g. "[ERROR]" or "warning:")
+	// define level of logged entry instead of Level. Prefixes are removed from messages.
+	ParseLevel bool
+	// MaxLineLength limits length of logged lines in bytes. Longer lines (e.g. progress
+	// reports ended with '\r' only) are logged in parts. If it is not positive,
+	// DefaultMaxLineLength is used.
+	MaxLineLength int
+	// mutex protects lines.
+	mutex sync.Mutex
+	// lines holds incomplete line.
+	lines lineBuffer
+}
+
+// NewLineWriter creates and returns a new LineWriter logging lines to l with given level
+// and parsing level prefixes. Lines are limited to DefaultMaxLineLength bytes.
+func NewLineWriter(l *Logger, level Level) *LineWriter {
+	return &LineWriter{
+		Logger:        l,
+		Level:         level,
+		ParseLevel:    true,
+		MaxLineLength: DefaultMaxLineLength,
+	}
+}
+
+// Write logs all complete lines from p. Incomplete line is kept until it is completed
+// by the next Write, reaches MaxLineLength or is logged by Flush. It implements io.Writer
+// interface in LineWriter. Call context of logged lines is the caller of Write. Frames of standard
+// log package are skipped, so lines written with it have call context of its caller.
+func (w *LineWriter) Write(p []byte) (int, error) {
+	// Skip Write. Bridge frames are skipped by depth, not marked with Helper, so capturing
+	// of call context of other logging calls stays cheap.
+	pc := findCallerPC(1, maxCallContextDepth)
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.lines.write(p, w.MaxLineLength, func(line string) {
+		w.logLine(pc, line)
+	})
+	return len(p), nil
+}
+
+// Flush logs incomplete line. It implements Flusher interface in LineWriter.
+func (w *LineWriter) Flush() error {
+	w.flush(getCallerPC(1))
+	return nil
+}
+
+// Close flushes LineWriter. It implements io.Closer interface in LineWriter.
+func (w *LineWriter) Close() error {
+	w.flush(getCallerPC(1))
+	return nil
+}
+
+// flush logs incomplete line with call context of given program counter.
+func (w *LineWriter) flush(pc uintptr) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.lines.flush(func(line string) {
+		w.logLine(pc, line)
+	})
+}
+
+// logLine logs a single line with call context of given program counter. Empty lines are omitted.
+func (w *LineWriter) logLine(pc uintptr, line string) {
+	line = strings.TrimSuffix(line, "\r")
+	level := w.Level
+	if w.ParseLevel {
+		level, line = parseLevelPrefix(line, level)
+	}
+	if line == "" {
+		return
+	}
+	w.Logger.newEntry().WithCaller(pc).Log(level, line)
+}
+
+// parseLevelPrefix returns level defined by prefix of line ("[LEVEL]" or "LEVEL:") and the rest
+// of the line. If line has no level prefix, given level and unchanged line are returned.
+func parseLevelPrefix(line string, level Level) (Level, string) {
+	trimmed := strings.TrimLeft(line, " \t")
+	var name, rest string
+	if strings.HasPrefix(trimmed, "[") {
+		end := strings.IndexByte(trimmed, ']')
+		if end < 0 {
+			return level, line
+		}
+		name, rest = trimmed[1:end], trimmed[end+1:]
+	} else {
+		end := strings.IndexByte(trimmed, ':')
+		if end < 0 || end > maxLevelPrefixLength {
+			return level, line
+		}
+		name, rest = trimmed[:end], trimmed[end+1:]
+	}
+	l, ok := levelPrefixes[strings.ToLower(name)]
+	if !ok {
+		return level, line
+	}
+	return l, strings.TrimLeft(rest, " \t")
+}
+
+// LineWriter returns a new LineWriter logging lines with given level.
+func (l *Logger) LineWriter(level Level) *LineWriter {
+	return NewLineWriter(l, level)
+}
+
+// StdLogger returns standard log package's Logger logging messages with given level (unless
+// they have a level prefix).
+func (l *Logger) StdLogger(level Level) *log.Logger {
+	return log.New(l.LineWriter(level), "", 0)
+}
+
+// RedirectStdLog makes output of standard log package's functions logged with given level (unless
+// they have a level prefix). Flags and prefix of standard logger are cleared, as timestamp
+// and call context are added by Logger. The returned function restores previous configuration
+// of standard logger.
+func (l *Logger) RedirectStdLog(level Level) (restore func()) {
+	output, flags, prefix := log.Writer(), log.Flags(), log.Prefix()
+	log.SetOutput(l.LineWriter(level))
+	log.SetFlags(0)
+	log.SetPrefix("")
+	return func() {
+		log.SetOutput(output)
+		log.SetFlags(flags)
+		log.SetPrefix(prefix)
+	}
+}
@@ -0,0 +1,167 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"fmt"
+	"log"
+	"runtime"
+
+	. "github.com/onsi/ginkgo"
+	T "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LineWriter", func() {
+	const thisFile = "line_writer_test.go"
+	var (
+		L *Logger
+		f *filterCollector
+		w *LineWriter
+	)
+
+	BeforeEach(func() {
+		L = NewLogger()
+		L.SetThreshold(DebugLevel)
+		f = new(filterCollector)
+		L.AddBackend("collector", Backend{
+			Filter:     f,
+			Serializer: NewSerializerJSON(),
+			Writer:     new(writerCollector),
+		})
+		w = L.LineWriter(NoticeLevel)
+	})
+
+	messages := func() (ret []string) {
+		for _, e := range f.Entries() {
+			ret = append(ret, fmt.Sprintf("%s %s", e.Level, e.Message))
+		}
+		return ret
+	}
+
+	It("should create a new object with default configuration", func() {
+		Expect(w.Logger).To(Equal(L))
+		Expect(w.Level).To(Equal(NoticeLevel))
+		Expect(w.ParseLevel).To(BeTrue())
+		Expect(w.MaxLineLength).To(Equal(DefaultMaxLineLength))
+	})
+	It("should log every complete line", func() {
+		n, err := w.Write([]byte("first\nsec"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(n).To(Equal(9))
+		Expect(messages()).To(Equal([]string{"notice first"}))
+
+		_, err = w.Write([]byte("ond\r\n\nthird\n"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(messages()).To(Equal([]string{"notice first", "notice second", "notice third"}))
+	})
+	It("should log incomplete line when flushed or closed", func() {
+		_, err := w.Write([]byte("first"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(w.Flush()).To(Succeed())
+		Expect(messages()).To(Equal([]string{"notice first"}))
+		Expect(w.Flush()).To(Succeed())
+		Expect(messages()).To(HaveLen(1))
+
+		_, err = w.Write([]byte("second"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(w.Close()).To(Succeed())
+		Expect(messages()).To(Equal([]string{"notice first", "notice second"}))
+	})
+	It("should log parts of lines longer than the limit", func() {
+		w.MaxLineLength = 4
+		_, err := w.Write([]byte("10%\r20%\r30%\r"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(messages()).To(Equal([]string{"notice 10%", "notice 20%"}))
+		Expect(w.lines.buf).To(HaveLen(4))
+
+		_, err = w.Write([]byte("\nabcdefgh\n"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(messages()[2:]).To(Equal([]string{"notice 30%", "notice abcd", "notice efgh"}))
+		Expect(w.lines.buf).To(BeEmpty())
+	})
+	It("should not split characters of long lines", func() {
+		w.MaxLineLength = 4
+		_, err := w.Write([]byte("abcżółw\n"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(messages()).To(Equal([]string{"notice abc", "notice żó", "notice łw"}))
+	})
+	It("should not parse level prefixes if disabled", func() {
+		w.ParseLevel = false
+		_, err := w.Write([]byte("[ERROR] failed\n"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(messages()).To(Equal([]string{"notice [ERROR] failed"}))
+	})
+	It("should use call context of writer's caller", func() {
+		_, _, line, _ := runtime.Caller(0)
+		_, err := w.Write([]byte("message\nincomplete"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(w.Flush()).To(Succeed())
+		entries := f.Entries()
+		Expect(entries).To(HaveLen(2))
+		Expect(entries[0].CallContext.File).To(Equal(thisFile))
+		Expect(entries[0].CallContext.Line).To(Equal(line + 1))
+		Expect(entries[1].CallContext.File).To(Equal(thisFile))
+		Expect(entries[1].CallContext.Line).To(Equal(line + 3))
+		Expect(markedAsHelper((*LineWriter).Write)).To(BeFalse())
+		Expect(markedAsHelper((*LineWriter).Flush)).To(BeFalse())
+	})
+	T.DescribeTable("should parse level prefixes",
+		func(line string, level Level, message string) {
+			l, m := parseLevelPrefix(line, NoticeLevel)
+			Expect(l).To(Equal(level))
+			Expect(m).To(Equal(message))
+		},
+		T.Entry("brackets", "[ERROR] failed", ErrLevel, "failed"),
+		T.Entry("short name", "[WARN]failed", WarningLevel, "failed"),
+		T.Entry("colon", "  debug:  value", DebugLevel, "value"),
+		T.Entry("fatal", "FATAL: crashed", CritLevel, "crashed"),
+		T.Entry("unknown name", "[worker] started", NoticeLevel, "[worker] started"),
+		T.Entry("unclosed bracket", "[ERROR failed", NoticeLevel, "[ERROR failed"),
+		T.Entry("long name", "connection: lost", NoticeLevel, "connection: lost"),
+		T.Entry("no prefix", "plain text", NoticeLevel, "plain text"),
+	)
+	Describe("StdLogger", func() {
+		It("should log messages of standard logger", func() {
+			std := L.StdLogger(InfoLevel)
+			_, _, line, _ := runtime.Caller(0)
+			std.Printf("[warning] value %d", 7)
+			Expect(messages()).To(Equal([]string{"warning value 7"}))
+			e := f.Entries()[0]
+			Expect(e.CallContext.File).To(Equal(thisFile))
+			Expect(e.CallContext.Line).To(Equal(line + 1))
+		})
+	})
+	Describe("RedirectStdLog", func() {
+		It("should redirect and restore standard logger", func() {
+			output, flags, prefix := log.Writer(), log.Flags(), log.Prefix()
+			restore := L.RedirectStdLog(InfoLevel)
+			Expect(log.Flags()).To(BeZero())
+			_, _, line, _ := runtime.Caller(0)
+			log.Print("message")
+			restore()
+
+			Expect(messages()).To(Equal([]string{"info message"}))
+			e := f.Entries()[0]
+			Expect(e.CallContext.File).To(Equal(thisFile))
+			Expect(e.CallContext.Line).To(Equal(line + 1))
+			Expect(log.Writer()).To(Equal(output))
+			Expect(log.Flags()).To(Equal(flags))
+			Expect(log.Prefix()).To(Equal(prefix))
+		})
+	})
+})
@@ -17,8 +17,8 @@
 package logger
 
 import (
+	"expvar"
 	"fmt"
-	"os"
 	"sync"
 	"sync/atomic"
 )
@@ -35,21 +35,90 @@ type Logger struct {
 	// The default threshold is set to InfoLevel.
 	threshold Level
 
-	// mutex protects Logger structure from concurrent access.
+	// mutex serializes modifications of Logger's configuration.
+	// It is never taken while logging.
 	mutex *sync.Mutex
 
-	// backends contains all Backends used currently by the Logger.
+	// dispatcher holds current *dispatcher. It is replaced as a whole on every modification
+	// (copy-on-write), so entries can be dispatched without locking.
+	dispatcher atomic.Value
+}
+
+// dispatcher is an immutable snapshot of Logger's backends configuration.
+// It must not be modified after being stored in Logger.
+type dispatcher struct {
+	// backends contains all Backends used by the Logger.
 	backends map[string]Backend
+
+	// stats contains statistics of all Backends used by the Logger.
+	stats map[string]*backendStats
+
+	// errorHandler handles failures of backends.
+	errorHandler ErrorHandler
+
+	// gate is errorHandler's BackendGate interface or nil if not implemented.
+	gate BackendGate
+
+	// parallel set to true makes entries passed to all backends concurrently.
+	parallel bool
+
+	// stackThreshold defines level of entries which get stack trace attached.
+	// Only entries with level equal or less than stackThreshold get it.
+	// It is used only if stackEnabled is set to true.
+	stackThreshold Level
+
+	// stackEnabled set to true enables attaching stack traces by stackThreshold.
+	stackEnabled bool
+
+	// enrichers are run on every entry before passing it to backends.
+	enrichers []Enricher
+}
+
+// clone returns a copy of dispatcher which can be modified.
+func (d *dispatcher) clone() *dispatcher {
+	c := *d
+	c.backends = make(map[string]Backend, len(d.backends))
+	for k, v := range d.backends {
+		c.backends[k] = v
+	}
+	c.stats = make(map[string]*backendStats, len(d.stats))
+	for k, v := range d.stats {
+		c.stats[k] = v
+	}
+	return &c
 }
 
 // NewLogger creates a new Logger instance with default configuration.
 // Default level threshold is set to InfoLevel.
 func NewLogger() *Logger {
-	return &Logger{
+	l := &Logger{
 		threshold: DefaultThreshold,
 		mutex:     new(sync.Mutex),
-		backends:  make(map[string]Backend),
 	}
+	l.dispatcher.Store(&dispatcher{
+		backends:     make(map[string]Backend),
+		stats:        make(map[string]*backendStats),
+		errorHandler: NewErrorHandlerPrint(),
+	})
+	return l
+}
+
+// load returns current dispatcher of the Logger.
+func (l *Logger) load() *dispatcher {
+	return l.dispatcher.Load().(*dispatcher)
+}
+
+// update modifies a copy of current dispatcher with given function and replaces current one.
+// If modify returns an error, dispatcher is not replaced.
+func (l *Logger) update(modify func(*dispatcher) error) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	d := l.load().clone()
+	if err := modify(d); err != nil {
+		return err
+	}
+	l.dispatcher.Store(d)
+	return nil
 }
 
 // SetThreshold defines Logger's filter level.
@@ -74,32 +143,152 @@ func (l *Logger) PassThreshold(level Level) bool {
 }
 
 // AddBackend adds or replaces a backend with given name.
+// Statistics of replaced backend are preserved.
 func (l *Logger) AddBackend(name string, b Backend) {
-	l.mutex.Lock()
-	defer l.mutex.Unlock()
 	b.Logger = l
-	l.backends[name] = b
+	_ = l.update(func(d *dispatcher) error {
+		d.backends[name] = b
+		if _, ok := d.stats[name]; !ok {
+			d.stats[name] = newBackendStats()
+		}
+		return nil
+	})
 }
 
 // RemoveBackend removes a backend with given name.
 func (l *Logger) RemoveBackend(name string) error {
-	l.mutex.Lock()
-	defer l.mutex.Unlock()
-
-	_, ok := l.backends[name]
-	if !ok {
-		return ErrInvalidBackendName
-	}
+	return l.update(func(d *dispatcher) error {
+		_, ok := d.backends[name]
+		if !ok {
+			return ErrInvalidBackendName
+		}
 
-	delete(l.backends, name)
-	return nil
+		delete(d.backends, name)
+		delete(d.stats, name)
+		return nil
+	})
 }
 
 // RemoveAllBackends clears all backends.
 func (l *Logger) RemoveAllBackends() {
-	l.mutex.Lock()
-	defer l.mutex.Unlock()
-	l.backends = make(map[string]Backend)
+	_ = l.update(func(d *dispatcher) error {
+		d.backends = make(map[string]Backend)
+		d.stats = make(map[string]*backendStats)
+		return nil
+	})
+}
+
+// Stats returns statistics of all backends.
+func (l *Logger) Stats() map[string]BackendStats {
+	d := l.load()
+	ret := make(map[string]BackendStats, len(d.stats))
+	for name, s := range d.stats {
+		ret[name] = s.snapshot()
+	}
+	return ret
+}
+
+// PublishStats publishes statistics of all backends as expvar variable with given name.
+// Like expvar.Publish, it panics if variable with given name is already published.
+func (l *Logger) PublishStats(name string) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return l.Stats()
+	}))
+}
+
+// AddEnricher adds enricher run on every entry passing threshold before it is passed
+// to backends. Enrichers are run in order in which they were added.
+func (l *Logger) AddEnricher(e Enricher) {
+	_ = l.update(func(d *dispatcher) error {
+		d.enrichers = append(d.enrichers[:len(d.enrichers):len(d.enrichers)], e)
+		return nil
+	})
+}
+
+// RemoveAllEnrichers removes all enrichers added with AddEnricher.
+func (l *Logger) RemoveAllEnrichers() {
+	_ = l.update(func(d *dispatcher) error {
+		d.enrichers = nil
+		return nil
+	})
+}
+
+// enrich runs all enrichers on entry.
+func (d *dispatcher) enrich(entry *Entry) {
+	for _, e := range d.enrichers {
+		e.Enrich(entry)
+	}
+}
+
+// SetErrorHandler sets handler of backends' failures.
+// Setting nil handler restores the default ErrorHandlerPrint.
+func (l *Logger) SetErrorHandler(h ErrorHandler) {
+	if h == nil {
+		h = NewErrorHandlerPrint()
+	}
+	_ = l.update(func(d *dispatcher) error {
+		d.errorHandler = h
+		d.gate = findBackendGate(h)
+		return nil
+	})
+}
+
+// SetParallel enables or disables parallel dispatching. When enabled, every entry is passed
+// to all backends concurrently, so a slow backend does not delay the others. Logging call
+// still returns after all backends finish processing the entry.
+func (l *Logger) SetParallel(parallel bool) {
+	_ = l.update(func(d *dispatcher) error {
+		d.parallel = parallel
+		return nil
+	})
+}
+
+// usesCallContext returns true if any of backends uses call context.
+func (d *dispatcher) usesCallContext() bool {
+	for _, backend := range d.backends {
+		if backend.usesCallContext() {
+			return true
+		}
+	}
+	return false
+}
+
+// SetStackThreshold makes Logger attach stack trace to entries with level equal or less than
+// given level. Stack traces are not attached by default.
+func (l *Logger) SetStackThreshold(level Level) error {
+	if !level.IsValid() {
+		return ErrInvalidLogLevel
+	}
+	return l.update(func(d *dispatcher) error {
+		d.stackThreshold = level
+		d.stackEnabled = true
+		return nil
+	})
+}
+
+// ResetStackThreshold stops attaching stack traces to entries by Logger's policy.
+// Stack traces are still attached to entries requesting it with WithStack.
+func (l *Logger) ResetStackThreshold() {
+	_ = l.update(func(d *dispatcher) error {
+		d.stackEnabled = false
+		return nil
+	})
+}
+
+// passStackThreshold verifies if entry with given level should get stack trace attached.
+func (d *dispatcher) passStackThreshold(level Level) bool {
+	return d.stackEnabled && level <= d.stackThreshold
+}
+
+// Flush writes entries held by backends (see Flusher interface). It should be called before
+// the process exits. All backends are flushed even if some fail. The first error is returned.
+func (l *Logger) Flush() (err error) {
+	for _, backend := range l.load().backends {
+		if ferr := backend.flush(); ferr != nil && err == nil {
+			err = ferr
+		}
+	}
+	return err
 }
 
 // newEntry creates a new log entry.
@@ -110,108 +299,187 @@ func (l *Logger) newEntry() *Entry {
 	}
 }
 
-// process pass entry to backends.
+// process pass entry to backends. Summaries of limiting filters are passed only to the backend
+// using the filter or to all backends if no backend uses the filter (e.g. HTTPHandler's
+// Sampler). Failures of backends are passed to the error handler. Backends disabled by the error
+// handler (see BackendGate) are skipped.
 func (l *Logger) process(entry *Entry) {
-	l.mutex.Lock()
-	defer l.mutex.Unlock()
-	for name, backend := range l.backends {
-		err := backend.process(entry)
-		if err != nil {
-			// The error is printed to stderr. Potential fail of printing is ignored.
-			_, _ = fmt.Fprintf(os.Stderr, "Error <%s> printing log message to <%s> backend.\n",
-				err.Error(), name)
+	d := l.load()
+	if entry.summaryOf != nil {
+		if name, backend, ok := d.findBackend(entry.summaryOf); ok {
+			d.processBackend(name, backend, entry)
+			return
 		}
 	}
+	if !d.parallel || len(d.backends) < 2 {
+		for name, backend := range d.backends {
+			d.processBackend(name, backend, entry)
+		}
+		return
+	}
+	wg := new(sync.WaitGroup)
+	wg.Add(len(d.backends))
+	for name, backend := range d.backends {
+		go func(name string, backend Backend) {
+			defer wg.Done()
+			d.processBackend(name, backend, entry)
+		}(name, backend)
+	}
+	wg.Wait()
+}
+
+// processBackend pass entry to a single backend.
+func (d *dispatcher) processBackend(name string, backend Backend, entry *Entry) {
+	stats := d.stats[name]
+	if d.gate != nil && !d.gate.Allow(name) {
+		stats.skipped(entry.Level)
+		return
+	}
+	err := backend.processStats(entry, stats)
+	if err != nil {
+		// Entry may be reused after the logging call, while handler may keep it.
+		d.errorHandler.HandleError(name, entry.clone(), err)
+	} else if d.gate != nil {
+		d.gate.Succeeded(name)
+	}
+}
+
+// handleFilterError passes failure of backend using given filter, which happened outside
+// of a logging call (e.g. in a timer), to the error handler. If l is nil, the error is printed
+// with ErrorHandlerPrint.
+func (l *Logger) handleFilterError(filter Filter, entry *Entry, err error) {
+	if l == nil {
+		NewErrorHandlerPrint().HandleError("", entry, err)
+		return
+	}
+	d := l.load()
+	name, _, _ := d.findBackend(filter)
+	d.errorHandler.HandleError(name, entry, err)
+}
+
+// findBackend returns name of the backend using given filter directly or wrapped
+// (see filterWrapper) and the backend itself. It returns false if there is no such backend.
+func (d *dispatcher) findBackend(filter Filter) (string, Backend, bool) {
+	for name, backend := range d.backends {
+		if usesFilter(backend.Filter, filter) {
+			return name, backend, true
+		}
+	}
+	return "", Backend{}, false
+}
+
+// log builds log message and logs it using a pooled entry. Nothing is allocated if level
+// does not pass threshold. It must be called directly by the exported logging methods
+// for proper call context calculation.
+func (l *Logger) log(level Level, args []interface{}) {
+	if !l.PassThreshold(level) {
+		return
+	}
+	e := getEntry(l)
+	e.IncDepth(2).process(level, sprint(args))
+	putEntry(e)
+}
+
+// logf builds formatted log message and logs it using a pooled entry. Nothing is allocated
+// if level does not pass threshold. It must be called directly by the exported logging
+// methods for proper call context calculation.
+func (l *Logger) logf(level Level, format string, args []interface{}) {
+	if !l.PassThreshold(level) {
+		return
+	}
+	e := getEntry(l)
+	e.IncDepth(2).process(level, fmt.Sprintf(format, args...))
+	putEntry(e)
 }
 
 // Log builds log message and logs entry.
 func (l *Logger) Log(level Level, args ...interface{}) {
-	l.newEntry().IncDepth(1).Log(level, args...)
+	l.log(level, args)
 }
 
 // Logf builds formatted log message and logs entry.
 func (l *Logger) Logf(level Level, format string, args ...interface{}) {
-	l.newEntry().IncDepth(1).Logf(level, format, args...)
+	l.logf(level, format, args)
 }
 
 // Emergency logs emergency level message.
 func (l *Logger) Emergency(args ...interface{}) {
-	l.newEntry().IncDepth(1).Emergency(args...)
+	l.log(EmergLevel, args)
 }
 
 // Alert logs alert level message.
 func (l *Logger) Alert(args ...interface{}) {
-	l.newEntry().IncDepth(1).Alert(args...)
+	l.log(AlertLevel, args)
 }
 
 // Critical logs critical level message.
 func (l *Logger) Critical(args ...interface{}) {
-	l.newEntry().IncDepth(1).Critical(args...)
+	l.log(CritLevel, args)
 }
 
 // Error logs error level message.
 func (l *Logger) Error(args ...interface{}) {
-	l.newEntry().IncDepth(1).Error(args...)
+	l.log(ErrLevel, args)
 }
 
 // Warning logs warning level message.
 func (l *Logger) Warning(args ...interface{}) {
-	l.newEntry().IncDepth(1).Warning(args...)
+	l.log(WarningLevel, args)
 }
 
 // Notice logs notice level message.
 func (l *Logger) Notice(args ...interface{}) {
-	l.newEntry().IncDepth(1).Notice(args...)
+	l.log(NoticeLevel, args)
 }
 
 // Info logs info level message.
 func (l *Logger) Info(args ...interface{}) {
-	l.newEntry().IncDepth(1).Info(args...)
+	l.log(InfoLevel, args)
 }
 
 // Debug logs debug level message.
 func (l *Logger) Debug(args ...interface{}) {
-	l.newEntry().IncDepth(1).Debug(args...)
+	l.log(DebugLevel, args)
 }
 
 // Emergencyf logs emergency level formatted message.
 func (l *Logger) Emergencyf(format string, args ...interface{}) {
-	l.newEntry().IncDepth(1).Emergencyf(format, args...)
+	l.logf(EmergLevel, format, args)
 }
 
 // Alertf logs alert level formatted message.
 func (l *Logger) Alertf(format string, args ...interface{}) {
-	l.newEntry().IncDepth(1).Alertf(format, args...)
+	l.logf(AlertLevel, format, args)
 }
 
 // Criticalf logs critical level formatted message.
 func (l *Logger) Criticalf(format string, args ...interface{}) {
-	l.newEntry().IncDepth(1).Criticalf(format, args...)
+	l.logf(CritLevel, format, args)
 }
 
 // Errorf logs error level formatted message.
 func (l *Logger) Errorf(format string, args ...interface{}) {
-	l.newEntry().IncDepth(1).Errorf(format, args...)
+	l.logf(ErrLevel, format, args)
 }
 
 // Warningf logs warning level formatted message.
 func (l *Logger) Warningf(format string, args ...interface{}) {
-	l.newEntry().IncDepth(1).Warningf(format, args...)
+	l.logf(WarningLevel, format, args)
 }
 
 // Noticef logs notice level formatted message.
 func (l *Logger) Noticef(format string, args ...interface{}) {
-	l.newEntry().IncDepth(1).Noticef(format, args...)
+	l.logf(NoticeLevel, format, args)
 }
 
 // Infof logs info level formatted message.
 func (l *Logger) Infof(format string, args ...interface{}) {
-	l.newEntry().IncDepth(1).Infof(format, args...)
+	l.logf(InfoLevel, format, args)
 }
 
 // Debugf logs debug level formatted message.
 func (l *Logger) Debugf(format string, args ...interface{}) {
-	l.newEntry().IncDepth(1).Debugf(format, args...)
+	l.logf(DebugLevel, format, args)
 }
 
 // WithProperty creates a log message with a single property.
@@ -224,11 +492,21 @@ func (l *Logger) WithProperties(props Properties) *Entry {
 	return l.newEntry().WithProperties(props)
 }
 
+// WithFields creates a log message with typed fields.
+func (l *Logger) WithFields(fields ...Field) *Entry {
+	return l.newEntry().WithFields(fields...)
+}
+
 // WithError creates a log message with an error property.
 func (l *Logger) WithError(err error) *Entry {
 	return l.newEntry().WithError(err)
 }
 
+// WithStack creates a log message with stack trace attached.
+func (l *Logger) WithStack() *Entry {
+	return l.newEntry().WithStack()
+}
+
 // IncDepth increases depth of an Entry for call stack calculations.
 func (l *Logger) IncDepth(dep int) *Entry {
 	return l.newEntry().IncDepth(dep)
@@ -0,0 +1,125 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+// benchWriter is a Writer discarding data after an optional delay simulating slow destination.
+type benchWriter struct {
+	delay time.Duration
+}
+
+// Write implements Writer interface in benchWriter.
+func (w benchWriter) Write(_ Level, p []byte) (int, error) {
+	if w.delay > 0 {
+		time.Sleep(w.delay)
+	}
+	return len(p), nil
+}
+
+// newBenchLogger creates a Logger with given number of backends writing with given delay.
+func newBenchLogger(backends int, delay time.Duration, parallel bool) *Logger {
+	l := NewLogger()
+	for i := 0; i < backends; i++ {
+		l.AddBackend("bench"+strconv.Itoa(i), Backend{
+			Filter:     NewFilterPassAll(),
+			Serializer: NewSerializerJSON(),
+			Writer:     benchWriter{delay: delay},
+		})
+	}
+	l.SetParallel(parallel)
+	return l
+}
+
+// benchmarkLogger logs entries from given number of goroutines per GOMAXPROCS.
+func benchmarkLogger(b *testing.B, l *Logger, goroutines int) {
+	b.SetParallelism(goroutines)
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			l.WithProperty("key", "value").Info("Benchmark message.")
+		}
+	})
+}
+
+func BenchmarkLoggerBelowThreshold(b *testing.B) {
+	l := newBenchLogger(2, 0, false)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Debug("Benchmark message.")
+		l.Debugf("Benchmark message %s.", "formatted")
+	}
+}
+
+func BenchmarkLoggerGoroutines(b *testing.B) {
+	for _, goroutines := range []int{1, 16, 256} {
+		b.Run(strconv.Itoa(goroutines), func(b *testing.B) {
+			benchmarkLogger(b, newBenchLogger(2, 0, false), goroutines)
+		})
+	}
+}
+
+func BenchmarkLoggerSlowBackends(b *testing.B) {
+	for _, parallel := range []bool{false, true} {
+		b.Run("parallel="+strconv.FormatBool(parallel), func(b *testing.B) {
+			benchmarkLogger(b, newBenchLogger(4, 100*time.Microsecond, parallel), 16)
+		})
+	}
+}
+
+func BenchmarkLoggerAddBackendWhileLogging(b *testing.B) {
+	l := newBenchLogger(2, 0, false)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				l.AddBackend("bench0", Backend{
+					Filter:     NewFilterPassAll(),
+					Serializer: NewSerializerJSON(),
+					Writer:     benchWriter{},
+				})
+			}
+		}
+	}()
+	benchmarkLogger(b, l, 16)
+}
+
+func BenchmarkLoggerCallContext(b *testing.B) {
+	for _, mode := range []CallContextMode{CallContextModeNone, CallContextModeFunction} {
+		b.Run(strconv.Itoa(int(mode)), func(b *testing.B) {
+			s := NewSerializerText()
+			s.CallContextMode = mode
+			l := NewLogger()
+			l.AddBackend("bench", Backend{
+				Filter:     NewFilterPassAll(),
+				Serializer: s,
+				Writer:     benchWriter{},
+			})
+			benchmarkLogger(b, l, 1)
+		})
+	}
+}
@@ -18,7 +18,10 @@ package logger
 
 import (
 	"errors"
+	"expvar"
 	"runtime"
+	"testing"
+	"time"
 
 	gomock "github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo"
@@ -76,9 +79,11 @@ var _ = Describe("Logger", func() {
 			Expect(L).NotTo(BeNil())
 			Expect(L.mutex).NotTo(BeNil())
 			Expect(L.threshold).To(Equal(DefaultThreshold))
-			L.mutex.Lock()
-			defer L.mutex.Unlock()
-			Expect(L.backends).To(BeEmpty())
+			d := L.load()
+			Expect(d.backends).To(BeEmpty())
+			Expect(d.errorHandler).To(Equal(NewErrorHandlerPrint()))
+			Expect(d.stats).To(BeEmpty())
+			Expect(d.parallel).To(BeFalse())
 		})
 	})
 	Describe("Threshold", func() {
@@ -147,12 +152,11 @@ var _ = Describe("Logger", func() {
 	Describe("Backend", func() {
 		type Backends map[string]Backend
 		expectBackends := func(expected Backends) {
-			L.mutex.Lock()
-			defer L.mutex.Unlock()
-			Expect(L.backends).To(HaveLen(len(expected)))
+			backends := L.load().backends
+			Expect(backends).To(HaveLen(len(expected)))
 			for k, v := range expected {
 				v.Logger = L
-				Expect(L.backends).To(HaveKeyWithValue(k, v))
+				Expect(backends).To(HaveKeyWithValue(k, v))
 			}
 		}
 		BeforeEach(func() {
@@ -246,6 +250,27 @@ var _ = Describe("Logger", func() {
 			)
 			L.process(entry)
 		})
+		It("should log to all backends concurrently in parallel mode", func() {
+			L.SetParallel(true)
+			written := make(chan struct{})
+			gomock.InOrder(
+				mf.EXPECT().Verify(entry).Return(true, nil),
+				ms.EXPECT().Serialize(entry).Return(buf, nil),
+				mw.EXPECT().Write(entry.Level, buf).DoAndReturn(func(Level, []byte) (int, error) {
+					<-written
+					return len(buf), nil
+				}),
+			)
+			gomock.InOrder(
+				amf.EXPECT().Verify(entry).Return(true, nil),
+				ams.EXPECT().Serialize(entry).Return(buf, nil),
+				amw.EXPECT().Write(entry.Level, buf).DoAndReturn(func(Level, []byte) (int, error) {
+					close(written)
+					return len(buf), nil
+				}),
+			)
+			L.process(entry)
+		})
 		It("should print errors to stderr", func() {
 			mf.EXPECT().Verify(entry).Return(false, testError)
 			gomock.InOrder(
@@ -258,12 +283,223 @@ var _ = Describe("Logger", func() {
 			Expect(stderr).To(ContainSubstring(buildError(testError, backendName)))
 			Expect(stderr).To(ContainSubstring(buildError(testError, anotherBackendName)))
 		})
+		It("should pass errors to error handler", func() {
+			h := NewErrorHandlerCount(nil)
+			L.SetErrorHandler(h)
+			mf.EXPECT().Verify(entry).Return(false, testError)
+			amf.EXPECT().Verify(entry).Return(false, nil)
+			stderr := withStderrMocked(func() {
+				L.process(entry)
+			})
+			Expect(stderr).To(BeEmpty())
+			Expect(h.Errors()).To(Equal(map[string]uint64{backendName: 1}))
+		})
+	})
+	Describe("Stats", func() {
+		entry := &Entry{
+			Level:   ErrLevel,
+			Message: "Message",
+		}
+		buf := []byte("Lorem ipsum")
+		BeforeEach(func() {
+			L.AddBackend(backendName, mb)
+			L.AddBackend(anotherBackendName, amb)
+		})
+		It("should collect statistics of every backend", func() {
+			gomock.InOrder(
+				mf.EXPECT().Verify(entry).Return(true, nil),
+				ms.EXPECT().Serialize(entry).Return(buf, nil),
+				mw.EXPECT().Write(entry.Level, buf),
+			)
+			amf.EXPECT().Verify(entry).Return(false, nil)
+			L.process(entry)
+
+			stats := L.Stats()
+			Expect(stats).To(HaveLen(2))
+			Expect(stats[backendName].Counters).To(Equal(Counters{
+				Accepted: 1,
+				Bytes:    uint64(len(buf)),
+			}))
+			Expect(stats[backendName].Levels).To(HaveKey(ErrLevelStr))
+			Expect(stats[anotherBackendName].Counters).To(Equal(Counters{Filtered: 1}))
+		})
+		It("should preserve statistics of replaced backend", func() {
+			Expect(L.RemoveBackend(backendName)).To(Succeed())
+			amf.EXPECT().Verify(entry).Return(false, nil)
+			L.process(entry)
+			mf.EXPECT().Verify(entry).Return(false, nil)
+			L.AddBackend(anotherBackendName, mb)
+			L.process(entry)
+			Expect(L.Stats()[anotherBackendName].Filtered).To(Equal(uint64(2)))
+		})
+		It("should remove statistics of removed backends", func() {
+			Expect(L.RemoveBackend(backendName)).To(Succeed())
+			Expect(L.Stats()).To(HaveLen(1))
+			L.RemoveAllBackends()
+			Expect(L.Stats()).To(BeEmpty())
+		})
+		It("should count entries skipped by error handler", func() {
+			L.RemoveBackend(anotherBackendName)
+			h := NewErrorHandlerBreaker(1, time.Hour, nil)
+			L.SetErrorHandler(h)
+			mf.EXPECT().Verify(entry).Return(false, errors.New("Test Error"))
+			L.process(entry)
+			L.process(entry)
+			Expect(L.Stats()[backendName].Counters).To(Equal(Counters{
+				Errors:  1,
+				Skipped: 1,
+			}))
+		})
+		It("should publish statistics with expvar", func() {
+			const name = "loggerStatsTest"
+			L.PublishStats(name)
+			v := expvar.Get(name)
+			Expect(v).NotTo(BeNil())
+			Expect(v.String()).To(ContainSubstring(`"` + backendName + `":{"accepted":0,`))
+		})
+	})
+	Describe("StackThreshold", func() {
+		var stacks [][]CallContext
+		BeforeEach(func() {
+			stacks = nil
+			L.AddBackend(backendName, mb)
+			L.SetThreshold(DebugLevel)
+			mf.EXPECT().Verify(gomock.Any()).DoAndReturn(func(entry *Entry) (bool, error) {
+				stacks = append(stacks, entry.Stack)
+				return false, nil
+			}).AnyTimes()
+		})
+		It("should not attach stack traces by default", func() {
+			L.Emergency(testMessage)
+			Expect(stacks).To(Equal([][]CallContext{nil}))
+		})
+		It("should attach stack traces to entries passing stack threshold", func() {
+			Expect(L.SetStackThreshold(ErrLevel)).To(Succeed())
+			L.Critical(testMessage)
+			L.Error(testMessage)
+			L.Warning(testMessage)
+			Expect(stacks).To(HaveLen(3))
+			Expect(stacks[0]).NotTo(BeEmpty())
+			Expect(stacks[0][0].File).To(Equal(thisFile))
+			Expect(stacks[1]).NotTo(BeEmpty())
+			Expect(stacks[2]).To(BeNil())
+		})
+		It("should stop attaching stack traces after reset", func() {
+			Expect(L.SetStackThreshold(DebugLevel)).To(Succeed())
+			L.ResetStackThreshold()
+			L.Emergency(testMessage)
+			L.WithStack().Debug(testMessage)
+			Expect(stacks).To(HaveLen(2))
+			Expect(stacks[0]).To(BeNil())
+			Expect(stacks[1]).NotTo(BeEmpty())
+		})
+		It("should fail to set invalid level", func() {
+			Expect(L.SetStackThreshold(Level(0xBADC0DE))).To(Equal(ErrInvalidLogLevel))
+			Expect(L.load().stackEnabled).To(BeFalse())
+		})
+	})
+	Describe("Flush", func() {
+		It("should flush all backends and return first error", func() {
+			testError := errors.New("Test Error")
+			first := &flushCounter{err: testError}
+			second := &flushCounter{err: testError}
+			L.AddBackend(backendName, Backend{Filter: mf, Serializer: ms, Writer: first})
+			L.AddBackend(anotherBackendName, Backend{Filter: amf, Serializer: ams, Writer: second})
+			Expect(L.Flush()).To(Equal(testError))
+			Expect(first.flushed).To(Equal(1))
+			Expect(second.flushed).To(Equal(1))
+		})
+		It("should succeed without backends", func() {
+			Expect(L.Flush()).To(Succeed())
+		})
+	})
+	Describe("SetParallel", func() {
+		It("should enable and disable parallel dispatching", func() {
+			L.SetParallel(true)
+			Expect(L.load().parallel).To(BeTrue())
+			L.SetParallel(false)
+			Expect(L.load().parallel).To(BeFalse())
+		})
+		It("should preserve backends", func() {
+			L.AddBackend(backendName, mb)
+			L.SetParallel(true)
+			Expect(L.load().backends).To(HaveKey(backendName))
+		})
+	})
+	Describe("Enrichers", func() {
+		var (
+			w   *writerCollector
+			tag = EnricherFields{String("env", "test")}
+		)
+		BeforeEach(func() {
+			w = new(writerCollector)
+			L.AddBackend(backendName, Backend{
+				Filter:     NewFilterPassAll(),
+				Serializer: newPlainSerializerText(),
+				Writer:     w,
+			})
+		})
+		It("should run enrichers in order on every entry", func() {
+			L.AddEnricher(tag)
+			L.AddEnricher(EnricherFunc(func(e *Entry) {
+				e.WithFields(String("order", e.Fields[0].String))
+			}))
+			Expect(L.load().enrichers).To(HaveLen(2))
+			L.Info("message")
+			Expect(w.Messages()).To(Equal([]string{"[INF] message {env:test;order:test;}"}))
+		})
+		It("should not run enrichers on entries below threshold", func() {
+			L.AddEnricher(EnricherFunc(func(*Entry) {
+				Fail("enricher called")
+			}))
+			L.Debug("message")
+		})
+		It("should remove all enrichers", func() {
+			L.AddEnricher(tag)
+			L.RemoveAllEnrichers()
+			Expect(L.load().enrichers).To(BeEmpty())
+			L.Info("message")
+			Expect(w.Messages()).To(Equal([]string{"[INF] message "}))
+		})
+		It("should run backend's enrichers only on entries written by it", func() {
+			aw := new(writerCollector)
+			L.AddBackend(anotherBackendName, Backend{
+				Filter:     NewFilterPassAll(),
+				Serializer: newPlainSerializerText(),
+				Writer:     aw,
+				Enrichers:  []Enricher{tag},
+			})
+			L.Info("message")
+			Expect(w.Messages()).To(Equal([]string{"[INF] message "}))
+			Expect(aw.Messages()).To(Equal([]string{"[INF] message {env:test;}"}))
+		})
+	})
+	Describe("SetErrorHandler", func() {
+		It("should set error handler", func() {
+			h := NewErrorHandlerCount(nil)
+			L.SetErrorHandler(h)
+			Expect(L.load().errorHandler).To(BeIdenticalTo(h))
+		})
+		It("should restore default error handler if nil is given", func() {
+			L.SetErrorHandler(NewErrorHandlerCount(nil))
+			L.SetErrorHandler(nil)
+			Expect(L.load().errorHandler).To(Equal(NewErrorHandlerPrint()))
+		})
 	})
 	Describe("Log methods", func() {
 		BeforeEach(func() {
 			L.AddBackend(backendName, mb)
 			L.SetThreshold(DebugLevel)
 		})
+		It("should not allocate if level does not pass threshold", func() {
+			L.SetThreshold(ErrLevel)
+			allocs := testing.AllocsPerRun(100, func() {
+				L.Debug(testMessage, anotherTestMessage)
+				L.Infof(format, testMessage, anotherTestMessage)
+				L.Log(NoticeLevel, testMessage)
+			})
+			Expect(allocs).To(BeZero())
+		})
 		Describe("Log", func() {
 			T.DescribeTable("should properly build log message and pass to logger's backend",
 				func(level Level) {
@@ -293,7 +529,7 @@ var _ = Describe("Logger", func() {
 				func(level Level, testedFunction func(*Logger, ...interface{})) {
 					mf.EXPECT().Verify(gomock.Any()).DoAndReturn(func(entry *Entry) (bool, error) {
 						// Quite a deep stack through reflect, gomock and filter_mock.
-						_, _, line, _ := runtime.Caller(13)
+						_, _, line, _ := runtime.Caller(12)
 						Expect(entry.Level).To(Equal(level))
 						Expect(entry.Message).To(Equal(testMessage + anotherTestMessage))
 						Expect(entry.CallContext.File).To(Equal(thisFile))
@@ -342,7 +578,7 @@ var _ = Describe("Logger", func() {
 				func(level Level, testedFunction func(*Logger, string, ...interface{})) {
 					mf.EXPECT().Verify(gomock.Any()).DoAndReturn(func(entry *Entry) (bool, error) {
 						// Quite a deep stack through reflect, gomock and filter_mock.
-						_, _, line, _ := runtime.Caller(13)
+						_, _, line, _ := runtime.Caller(12)
 						Expect(entry.Level).To(Equal(level))
 						Expect(entry.Message).To(Equal(expectedMessage))
 						Expect(entry.CallContext.File).To(Equal(thisFile))
@@ -390,7 +626,21 @@ var _ = Describe("Logger", func() {
 		It("should create a new log message with an error property", func() {
 			entry := L.WithError(errorValue)
 			Expect(entry.Properties).To(HaveLen(1))
-			Expect(entry.Properties).To(HaveKeyWithValue(ErrorProperty, errorValue.Error()))
+			Expect(entry.Properties).To(HaveKeyWithValue(ErrorProperty, NewErrorValue(errorValue)))
+		})
+	})
+	Describe("WithFields", func() {
+		It("should create a new log message with fields", func() {
+			entry := L.WithFields(String("name", "Alice"), Int("age", 37))
+			Expect(entry.Fields).To(Equal([]Field{String("name", "Alice"), Int("age", 37)}))
+			Expect(entry.Logger).To(Equal(L))
+		})
+	})
+	Describe("WithStack", func() {
+		It("should create a new log message with stack trace requested", func() {
+			entry := L.WithStack()
+			Expect(entry.withStack).To(BeTrue())
+			Expect(entry.Logger).To(Equal(L))
 		})
 	})
 	Describe("IncDepth", func() {
@@ -0,0 +1,90 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+// Package logrusadapter provides logrus hook writing entries of logrus loggers
+// into SLAV logger.
+package logrusadapter
+
+import (
+	"github.com/SamsungSLAV/slav/logger"
+	"github.com/SamsungSLAV/slav/logger/internal/caller"
+	"github.com/sirupsen/logrus"
+)
+
+// logrusPackage is the import path of logrus. Its frames are skipped while looking for
+// the caller, if logrus does not report it.
+const logrusPackage = "github.com/sirupsen/logrus."
+
+// Level converts logrus level to logger.Level. Fatal and panic entries, which terminate
+// the program or the goroutine, get the most important levels.
+func Level(level logrus.Level) logger.Level {
+	switch level {
+	case logrus.PanicLevel:
+		return logger.AlertLevel
+	case logrus.FatalLevel:
+		return logger.EmergLevel
+	case logrus.ErrorLevel:
+		return logger.ErrLevel
+	case logrus.WarnLevel:
+		return logger.WarningLevel
+	case logrus.InfoLevel:
+		return logger.InfoLevel
+	}
+	return logger.DebugLevel
+}
+
+// Hook is a logrus.Hook logging entries with logger.Logger. Data of entries is logged
+// as properties with error values converted to logger.ErrorValue, error under logrus.ErrorKey
+// as logger's error property and caller reported by logrus (or the first function outside
+// of logrus) as call context.
+type Hook struct {
+	// Logger logs entries.
+	Logger *logger.Logger
+}
+
+// NewHook creates and returns a new Hook logging entries with l. Hook can be added
+// to logrus logger with AddHook. Output of logrus logger should be discarded,
+// e.g. with SetOutput(io.Discard), so that entries are not logged twice.
+func NewHook(l *logger.Logger) *Hook {
+	return &Hook{Logger: l}
+}
+
+// Levels returns all logrus levels. It implements logrus.Hook interface in Hook.
+func (h *Hook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire logs entry. It implements logrus.Hook interface in Hook.
+func (h *Hook) Fire(entry *logrus.Entry) error {
+	props := make(logger.Properties, len(entry.Data))
+	for k, v := range entry.Data {
+		if err, ok := v.(error); ok {
+			v = logger.NewErrorValue(err)
+		}
+		props[k] = v
+	}
+	e := h.Logger.WithProperties(props)
+	if err, ok := entry.Data[logrus.ErrorKey].(error); ok {
+		e.WithError(err)
+	}
+	if entry.Caller != nil {
+		e.WithCaller(caller.FramePC(*entry.Caller))
+	} else {
+		e.WithCaller(caller.PC(logrusPackage))
+	}
+	e.Log(Level(entry.Level), entry.Message)
+	return nil
+}
@@ -0,0 +1,106 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logrusadapter
+
+import (
+	"errors"
+	"io"
+	"runtime"
+
+	"github.com/SamsungSLAV/slav/logger"
+	"github.com/SamsungSLAV/slav/logger/internal/loggertest"
+	. "github.com/onsi/ginkgo"
+	T "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+)
+
+const thisFile = "hook_test.go"
+
+var _ = Describe("Hook", func() {
+	var (
+		L *logger.Logger
+		c *loggertest.Collector
+		r *logrus.Logger
+	)
+
+	BeforeEach(func() {
+		L = logger.NewLogger()
+		Expect(L.SetThreshold(logger.DebugLevel)).To(Succeed())
+		c = new(loggertest.Collector)
+		L.AddBackend("collector", logger.Backend{
+			Filter:     c,
+			Serializer: logger.NewSerializerJSON(),
+			Writer:     logger.NewWriterStderr(),
+		})
+		r = logrus.New()
+		r.SetOutput(io.Discard)
+		r.SetLevel(logrus.TraceLevel)
+		r.AddHook(NewHook(L))
+	})
+
+	It("should log entries with data as properties", func() {
+		testError := errors.New("test error")
+		r.WithFields(logrus.Fields{"name": "alice", "count": 3}).WithError(testError).
+			Warn("message")
+		e := c.Last()
+		Expect(e.Level).To(Equal(logger.WarningLevel))
+		Expect(e.Message).To(Equal("message"))
+		Expect(e.Properties).To(Equal(logger.Properties{
+			"name":               "alice",
+			"count":              3,
+			logger.ErrorProperty: logger.NewErrorValue(testError),
+		}))
+	})
+	It("should convert all error values of data", func() {
+		testError := errors.New("test error")
+		r.WithField("cause", testError).Error("message")
+		Expect(c.Last().Properties).To(Equal(logger.Properties{
+			"cause": logger.NewErrorValue(testError),
+		}))
+	})
+	It("should use caller reported by logrus", func() {
+		r.SetReportCaller(true)
+		_, _, line, _ := runtime.Caller(0)
+		r.Info("message")
+		e := c.Last()
+		Expect(e.CallContext.File).To(Equal(thisFile))
+		Expect(e.CallContext.Line).To(Equal(line + 1))
+	})
+	It("should find caller outside of logrus", func() {
+		_, _, line, _ := runtime.Caller(0)
+		r.WithField("a", 1).Infof("message %d", 1)
+		e := c.Last()
+		Expect(e.CallContext.File).To(Equal(thisFile))
+		Expect(e.CallContext.Line).To(Equal(line + 1))
+	})
+	It("should fire for all levels", func() {
+		Expect(NewHook(L).Levels()).To(Equal(logrus.AllLevels))
+	})
+	T.DescribeTable("should map levels",
+		func(level logrus.Level, expected logger.Level) {
+			Expect(Level(level)).To(Equal(expected))
+		},
+		T.Entry("panic", logrus.PanicLevel, logger.AlertLevel),
+		T.Entry("fatal", logrus.FatalLevel, logger.EmergLevel),
+		T.Entry("error", logrus.ErrorLevel, logger.ErrLevel),
+		T.Entry("warning", logrus.WarnLevel, logger.WarningLevel),
+		T.Entry("info", logrus.InfoLevel, logger.InfoLevel),
+		T.Entry("debug", logrus.DebugLevel, logger.DebugLevel),
+		T.Entry("trace", logrus.TraceLevel, logger.DebugLevel),
+	)
+})
@@ -0,0 +1,362 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// DefaultMetricsNamespace is the default prefix of metrics names.
+	DefaultMetricsNamespace = "slav_log"
+	// MetricsOtherValue is the label value used for property values not listed in whitelist.
+	MetricsOtherValue = "other"
+	// metricsContentType is the content type of Prometheus text exposition format.
+	metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+// metricsCount is a single counter of Metrics.
+type metricsCount struct {
+	// labels contains values of labels in order: logger, level and properties.
+	labels []string
+	// count is the number of counted entries.
+	count uint64
+}
+
+// CallSite contains number of entries logged in a single place in source code.
+type CallSite struct {
+	// CallContext identifies the place in source code.
+	CallContext
+	// Count is the number of entries logged in the place.
+	Count uint64
+}
+
+// metricsLabels contains names of labels of every counter of Metrics.
+var metricsLabels = []string{"logger", "level"}
+
+// Metrics counts entries by level, logger name and values of chosen properties.
+// It serves counters in Prometheus text exposition format and implements http.Handler
+// interface for that purpose. It also counts entries by their call context, so places
+// producing the most entries can be found with TopTalkers.
+//
+// Use Backend method to get a Backend counting entries of a Logger.
+type Metrics struct {
+	// Namespace defines prefix of metrics names. Characters not allowed in Prometheus metric
+	// names are replaced with '_'.
+	Namespace string
+
+	// properties maps names of properties used as labels to whitelists of their values.
+	// Nil whitelist means that all values are used. The map is never modified after being
+	// stored; it is replaced as a whole, so it can be used after unlocking mutex.
+	properties map[string]map[string]bool
+	// propertyNames contains sorted names of properties used as labels. Like properties,
+	// it is replaced as a whole.
+	propertyNames []string
+	// counters contains counters for every set of labels.
+	counters map[string]*metricsCount
+	// callSites contains counters for every call context.
+	callSites map[string]*CallSite
+	// mutex protects Metrics structure from concurrent access.
+	mutex *sync.Mutex
+}
+
+// NewMetrics creates and returns a new Metrics object with default configuration.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		Namespace:  DefaultMetricsNamespace,
+		properties: make(map[string]map[string]bool),
+		counters:   make(map[string]*metricsCount),
+		callSites:  make(map[string]*CallSite),
+		mutex:      new(sync.Mutex),
+	}
+}
+
+// AddProperty makes values of a property labels of counters. If values are given, only they
+// are used and all others are counted as MetricsOtherValue. Otherwise all values are used,
+// so it should be used only for properties with a small set of possible values.
+// Entries without property have empty label value. Counters are reset.
+// Characters of name not allowed in Prometheus label names are replaced with '_'.
+// ErrInvalidMetricsLabel is returned if such label name is empty, reserved or already used
+// by another label.
+func (m *Metrics) AddProperty(name string, values ...string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if err := m.verifyLabel(name); err != nil {
+		return err
+	}
+	var whitelist map[string]bool
+	if len(values) > 0 {
+		whitelist = make(map[string]bool, len(values))
+		for _, v := range values {
+			whitelist[v] = true
+		}
+	}
+	properties := make(map[string]map[string]bool, len(m.properties)+1)
+	for k, v := range m.properties {
+		properties[k] = v
+	}
+	if _, ok := properties[name]; !ok {
+		names := append(append([]string(nil), m.propertyNames...), name)
+		sort.Strings(names)
+		m.propertyNames = names
+	}
+	properties[name] = whitelist
+	m.properties = properties
+	m.counters = make(map[string]*metricsCount)
+	return nil
+}
+
+// verifyLabel verifies if property name can be used as a label. It must be called with
+// mutex locked.
+func (m *Metrics) verifyLabel(name string) error {
+	label := sanitizeLabelName(name)
+	if label == "" || strings.HasPrefix(label, "__") {
+		return ErrInvalidMetricsLabel
+	}
+	for _, l := range metricsLabels {
+		if label == l {
+			return ErrInvalidMetricsLabel
+		}
+	}
+	for _, other := range m.propertyNames {
+		if other != name && sanitizeLabelName(other) == label {
+			return ErrInvalidMetricsLabel
+		}
+	}
+	return nil
+}
+
+// Backend returns a Backend counting all entries of a Logger and labeling them with given
+// logger name. The Backend does not write anything: its Filter rejects entries after counting
+// them, so Logger's Stats report all of them as filtered.
+func (m *Metrics) Backend(logger string) Backend {
+	c := &metricsCollector{
+		metrics: m,
+		logger:  logger,
+	}
+	return Backend{
+		Filter:     c,
+		Serializer: c,
+		Writer:     c,
+	}
+}
+
+// metricsLabelValues returns values of labels for entry logged by given logger with given
+// properties used as labels. Lazy values of properties are evaluated only if properties are used
+// as labels.
+func metricsLabelValues(logger string, entry *Entry, names []string,
+	properties map[string]map[string]bool) []string {
+
+	labels := make([]string, 0, len(metricsLabels)+len(names))
+	labels = append(labels, logger, entry.Level.String())
+	if len(names) > 0 {
+		entry = entry.resolved()
+	}
+	for _, name := range names {
+		v, ok := entry.property(name)
+		if !ok {
+			labels = append(labels, "")
+			continue
+		}
+		value := fmt.Sprint(v)
+		if whitelist := properties[name]; whitelist != nil && !whitelist[value] {
+			value = MetricsOtherValue
+		}
+		labels = append(labels, value)
+	}
+	return labels
+}
+
+// count counts entry logged by given logger. Labels are built without mutex locked, as resolving
+// lazy values may run user code, which could log with the same Logger.
+func (m *Metrics) count(logger string, entry *Entry) {
+	m.mutex.Lock()
+	names, properties := m.propertyNames, m.properties
+	m.mutex.Unlock()
+	labels := metricsLabelValues(logger, entry, names, properties)
+	key := strings.Join(labels, "\x00")
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	// Labels are counted only if properties have not been added in the meantime, as counters
+	// have been reset then.
+	if len(names) == len(m.propertyNames) {
+		c, ok := m.counters[key]
+		if !ok {
+			c = &metricsCount{labels: labels}
+			m.counters[key] = c
+		}
+		c.count++
+	}
+
+	if entry.CallContext == nil {
+		return
+	}
+	key = fmt.Sprintf("%s%s:%d", entry.CallContext.Path, entry.CallContext.File,
+		entry.CallContext.Line)
+	s, ok := m.callSites[key]
+	if !ok {
+		s = &CallSite{CallContext: *entry.CallContext}
+		m.callSites[key] = s
+	}
+	s.Count++
+}
+
+// TopTalkers returns up to n call sites which produced the most entries.
+// All call sites are returned if n is not positive.
+func (m *Metrics) TopTalkers(n int) []CallSite {
+	m.mutex.Lock()
+	ret := make([]CallSite, 0, len(m.callSites))
+	for _, s := range m.callSites {
+		ret = append(ret, *s)
+	}
+	m.mutex.Unlock()
+
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Count != ret[j].Count {
+			return ret[i].Count > ret[j].Count
+		}
+		if ret[i].Path+ret[i].File != ret[j].Path+ret[j].File {
+			return ret[i].Path+ret[i].File < ret[j].Path+ret[j].File
+		}
+		return ret[i].Line < ret[j].Line
+	})
+	if n > 0 && len(ret) > n {
+		ret = ret[:n]
+	}
+	return ret
+}
+
+// escapeLabelValue escapes label value according to Prometheus text exposition format.
+func escapeLabelValue(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+// sanitizeLabelName replaces characters not allowed in Prometheus label names with '_'.
+// Names starting with a digit are prefixed with '_'.
+func sanitizeLabelName(name string) string {
+	return sanitizeName(name, "")
+}
+
+// sanitizeMetricName replaces characters not allowed in Prometheus metric names with '_'.
+// Names starting with a digit are prefixed with '_'.
+func sanitizeMetricName(name string) string {
+	return sanitizeName(name, ":")
+}
+
+// sanitizeName replaces characters other than letters, digits, '_' and extra ones with '_'.
+// Names starting with a digit are prefixed with '_'.
+func sanitizeName(name, extra string) string {
+	name = strings.Map(func(r rune) rune {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') ||
+			(r >= '0' && r <= '9') || strings.ContainsRune(extra, r) {
+			return r
+		}
+		return '_'
+	}, name)
+	if len(name) > 0 && name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
+
+// WriteTo writes counters in Prometheus text exposition format to w.
+// It implements io.WriterTo interface.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mutex.Lock()
+	metric := sanitizeMetricName(m.Namespace + "_entries_total")
+	names := append(append([]string{}, metricsLabels...), m.propertyNames...)
+	lines := make([]string, 0, len(m.counters))
+	for _, c := range m.counters {
+		labels := make([]string, len(names))
+		for i, name := range names {
+			labels[i] = sanitizeLabelName(name) + `="` + escapeLabelValue(c.labels[i]) + `"`
+		}
+		lines = append(lines, fmt.Sprintf("%s{%s} %d", metric, strings.Join(labels, ","),
+			c.count))
+	}
+	m.mutex.Unlock()
+	sort.Strings(lines)
+
+	buf := new(bytes.Buffer)
+	// Writing to bytes.Buffer never fails.
+	_, _ = fmt.Fprintf(buf, "# HELP %s Number of log entries.\n", metric)
+	_, _ = fmt.Fprintf(buf, "# TYPE %s counter\n", metric)
+	for _, l := range lines {
+		_, _ = buf.WriteString(l + "\n")
+	}
+	return buf.WriteTo(w)
+}
+
+// ServeHTTP serves counters in Prometheus text exposition format.
+// It implements http.Handler interface in Metrics.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", metricsContentType)
+	// Potential fail of writing response is ignored as there is nothing to be done about it.
+	_, _ = m.WriteTo(w)
+}
+
+// TopTalkersHandler returns http.Handler serving up to n call sites which produced the most
+// entries in text format. Every line contains number of entries, file path with line number
+// and function name.
+func (m *Metrics) TopTalkersHandler(n int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		for _, s := range m.TopTalkers(n) {
+			// Potential fail of writing response is ignored.
+			_, _ = fmt.Fprintf(w, "%d %s%s:%d %s\n", s.Count, s.Path, s.File, s.Line,
+				s.funcName())
+		}
+	})
+}
+
+// metricsCollector counts entries of a single Logger in Metrics.
+// It implements Filter, Serializer and Writer interfaces.
+type metricsCollector struct {
+	// metrics collects counters.
+	metrics *Metrics
+	// logger is the name of Logger used as label value.
+	logger string
+}
+
+// Verify counts entry and rejects it, as there is nothing to be written.
+// It implements Filter interface in metricsCollector.
+func (c *metricsCollector) Verify(entry *Entry) (bool, error) {
+	if entry == nil {
+		return false, ErrInvalidEntry
+	}
+	c.metrics.count(c.logger, entry)
+	return false, nil
+}
+
+// Serialize is never called as all entries are rejected.
+// It implements Serializer interface in metricsCollector.
+func (*metricsCollector) Serialize(*Entry) ([]byte, error) {
+	return nil, nil
+}
+
+// Write is never called as all entries are rejected.
+// It implements Writer interface in metricsCollector.
+func (*metricsCollector) Write(Level, []byte) (int, error) {
+	return 0, nil
+}
@@ -0,0 +1,175 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Metrics", func() {
+	var (
+		m *Metrics
+		L *Logger
+	)
+
+	BeforeEach(func() {
+		m = NewMetrics()
+		L = NewLogger()
+		L.SetThreshold(DebugLevel)
+		L.AddBackend("metrics", m.Backend("boruta"))
+	})
+
+	scrape := func() string {
+		rec := httptest.NewRecorder()
+		m.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+		Expect(rec.Header().Get("Content-Type")).To(Equal(metricsContentType))
+		return rec.Body.String()
+	}
+
+	Describe("NewMetrics", func() {
+		It("should create a new object with default configuration", func() {
+			Expect(m).NotTo(BeNil())
+			Expect(m.Namespace).To(Equal(DefaultMetricsNamespace))
+		})
+	})
+	Describe("ServeHTTP", func() {
+		It("should serve only header without entries", func() {
+			Expect(scrape()).To(Equal("# HELP slav_log_entries_total Number of log entries.\n" +
+				"# TYPE slav_log_entries_total counter\n"))
+		})
+		It("should count entries by logger and level", func() {
+			L.Error("a")
+			L.Error("b")
+			L.Debug("c")
+			another := NewLogger()
+			another.AddBackend("metrics", m.Backend("weles"))
+			another.Warning("d")
+
+			Expect(scrape()).To(Equal("# HELP slav_log_entries_total Number of log entries.\n" +
+				"# TYPE slav_log_entries_total counter\n" +
+				`slav_log_entries_total{logger="boruta",level="debug"} 1` + "\n" +
+				`slav_log_entries_total{logger="boruta",level="error"} 2` + "\n" +
+				`slav_log_entries_total{logger="weles",level="warning"} 1` + "\n"))
+			Expect(L.Stats()["metrics"].Filtered).To(BeEquivalentTo(3))
+		})
+		It("should use whitelisted property values as labels", func() {
+			Expect(m.AddProperty("dryad.name", "A", "B")).To(Succeed())
+			Expect(m.AddProperty("job")).To(Succeed())
+			L.WithProperties(Properties{"dryad.name": "A", "job": 1}).Error("a")
+			L.WithProperties(Properties{"dryad.name": "C", "job": `"2"`}).Error("a")
+			L.Error("a")
+
+			Expect(scrape()).To(ContainSubstring(
+				`slav_log_entries_total{logger="boruta",level="error",dryad_name="",job=""} 1` +
+					"\n" +
+					`slav_log_entries_total{logger="boruta",level="error",dryad_name="A",job="1"}` +
+					" 1\n" +
+					`slav_log_entries_total{logger="boruta",level="error",dryad_name="other",` +
+					`job="\"2\""} 1` + "\n"))
+		})
+		It("should use values of lazy properties", func() {
+			Expect(m.AddProperty("job")).To(Succeed())
+			L.WithFields(Lazy("job", func() interface{} { return 7 })).Error("a")
+			Expect(scrape()).To(ContainSubstring(
+				`slav_log_entries_total{logger="boruta",level="error",job="7"} 1`))
+		})
+		It("should allow lazy properties to log with the same Logger", func() {
+			Expect(m.AddProperty("job")).To(Succeed())
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				L.WithFields(Lazy("job", func() interface{} {
+					L.Info("resolving")
+					return 7
+				})).Error("a")
+			}()
+			Eventually(done).Should(BeClosed())
+			Expect(scrape()).To(ContainSubstring(
+				`slav_log_entries_total{logger="boruta",level="error",job="7"} 1`))
+			Expect(scrape()).To(ContainSubstring(
+				`slav_log_entries_total{logger="boruta",level="info",job=""} 1`))
+		})
+		It("should use configured namespace", func() {
+			m.Namespace = "weles"
+			L.Info("a")
+			Expect(scrape()).To(ContainSubstring(`weles_entries_total{logger="boruta",` +
+				`level="info"} 1`))
+		})
+		It("should sanitize namespace", func() {
+			m.Namespace = "9weles-log:v2"
+			L.Info("a")
+			Expect(scrape()).To(ContainSubstring("# TYPE _9weles_log:v2_entries_total counter\n" +
+				`_9weles_log:v2_entries_total{logger="boruta",level="info"} 1`))
+		})
+	})
+	Describe("AddProperty", func() {
+		It("should reject names of labels which cannot be used", func() {
+			Expect(m.AddProperty("logger")).To(Equal(ErrInvalidMetricsLabel))
+			Expect(m.AddProperty("level")).To(Equal(ErrInvalidMetricsLabel))
+			Expect(m.AddProperty("")).To(Equal(ErrInvalidMetricsLabel))
+			Expect(m.AddProperty("__name__")).To(Equal(ErrInvalidMetricsLabel))
+			Expect(m.AddProperty("dryad.name")).To(Succeed())
+			Expect(m.AddProperty("dryad-name")).To(Equal(ErrInvalidMetricsLabel))
+			Expect(m.AddProperty("dryad.name", "A")).To(Succeed())
+			L.WithProperty("logger", "x").Info("a")
+			Expect(scrape()).To(ContainSubstring(
+				`slav_log_entries_total{logger="boruta",level="info",dryad_name=""} 1`))
+		})
+	})
+	Describe("TopTalkers", func() {
+		It("should return call sites producing the most entries", func() {
+			for i := 0; i < 3; i++ {
+				L.Info("loop")
+			}
+			L.Info("once")
+
+			top := m.TopTalkers(1)
+			Expect(top).To(HaveLen(1))
+			Expect(top[0].Count).To(Equal(uint64(3)))
+			Expect(top[0].File).To(Equal("metrics_test.go"))
+			Expect(m.TopTalkers(0)).To(HaveLen(2))
+		})
+		It("should serve call sites over HTTP", func() {
+			L.Info("once")
+			rec := httptest.NewRecorder()
+			m.TopTalkersHandler(10).ServeHTTP(rec, httptest.NewRequest("GET", "/top", nil))
+			Expect(rec.Body.String()).To(MatchRegexp(`^1 .*/metrics_test.go:\d+ ` +
+				thisPackage + `\..+\n$`))
+		})
+	})
+	Describe("metricsCollector", func() {
+		It("should reject all entries", func() {
+			b := m.Backend("boruta")
+			pass, err := b.Filter.Verify(&Entry{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pass).To(BeFalse())
+
+			_, err = b.Filter.Verify(nil)
+			Expect(err).To(Equal(ErrInvalidEntry))
+
+			buf, err := b.Serializer.Serialize(&Entry{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(buf).To(BeNil())
+			n, err := b.Writer.Write(InfoLevel, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n).To(BeZero())
+		})
+	})
+})
@@ -0,0 +1,105 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"sync"
+)
+
+const (
+	// defaultBufferSize is the initial capacity of pooled serialization buffers.
+	defaultBufferSize = 1 << 10
+	// maxPooledBufferSize limits capacity of buffers returned to pools, so a single huge entry
+	// does not keep a lot of memory allocated.
+	maxPooledBufferSize = 64 << 10
+)
+
+var (
+	// entryPool contains entries reused by logging methods of Logger.
+	entryPool = sync.Pool{
+		New: func() interface{} {
+			return new(Entry)
+		},
+	}
+
+	// bufferPool contains buffers for serialization of entries in Backend.
+	bufferPool = sync.Pool{
+		New: func() interface{} {
+			buf := make([]byte, 0, defaultBufferSize)
+			return &buf
+		},
+	}
+
+	// appendWriterPool contains writers used internally by SerializerText.
+	appendWriterPool = sync.Pool{
+		New: func() interface{} {
+			return new(appendWriter)
+		},
+	}
+)
+
+// getEntry returns an empty Entry from pool attached to given Logger.
+// The Entry must be returned with putEntry after processing and must not be used after that.
+func getEntry(l *Logger) *Entry {
+	e := entryPool.Get().(*Entry)
+	e.Logger = l
+	return e
+}
+
+// putEntry clears entry and returns it to pool.
+func putEntry(e *Entry) {
+	*e = Entry{}
+	entryPool.Put(e)
+}
+
+// getBuffer returns an empty buffer from pool.
+func getBuffer() *[]byte {
+	return bufferPool.Get().(*[]byte)
+}
+
+// putBuffer returns buffer to pool unless it grew too much.
+func putBuffer(buf *[]byte) {
+	if buf == nil || cap(*buf) > maxPooledBufferSize {
+		return
+	}
+	*buf = (*buf)[:0]
+	bufferPool.Put(buf)
+}
+
+// appendWriter is an io.Writer appending written data to a byte slice.
+type appendWriter struct {
+	buf []byte
+}
+
+// Write appends p to the slice. It implements io.Writer interface in appendWriter.
+func (w *appendWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	return len(p), nil
+}
+
+// getAppendWriter returns a writer from pool appending to dst.
+func getAppendWriter(dst []byte) *appendWriter {
+	w := appendWriterPool.Get().(*appendWriter)
+	w.buf = dst
+	return w
+}
+
+// putAppendWriter returns writer to pool. The slice it appended to is released.
+func putAppendWriter(w *appendWriter) {
+	w.buf = nil
+	appendWriterPool.Put(w)
+}
@@ -0,0 +1,73 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pools", func() {
+	Describe("getEntry", func() {
+		It("should return empty entry attached to logger", func() {
+			L := NewLogger()
+			e := getEntry(L)
+			Expect(*e).To(Equal(Entry{Logger: L}))
+			putEntry(e)
+		})
+	})
+	Describe("putEntry", func() {
+		It("should clear entry", func() {
+			e := &Entry{
+				Logger:      NewLogger(),
+				Level:       ErrLevel,
+				Message:     "message",
+				Properties:  Properties{"key": "value"},
+				CallContext: &CallContext{},
+				depth:       3,
+			}
+			putEntry(e)
+			Expect(*e).To(BeZero())
+		})
+	})
+	Describe("putBuffer", func() {
+		It("should accept nil buffer", func() {
+			Expect(func() { putBuffer(nil) }).NotTo(Panic())
+		})
+		It("should truncate pooled buffer", func() {
+			buf := getBuffer()
+			*buf = append(*buf, "data"...)
+			putBuffer(buf)
+			Expect(*buf).To(BeEmpty())
+		})
+		It("should not truncate too big buffer", func() {
+			buf := make([]byte, 1, maxPooledBufferSize+1)
+			putBuffer(&buf)
+			Expect(buf).To(HaveLen(1))
+		})
+	})
+	Describe("getAppendWriter", func() {
+		It("should append to given slice", func() {
+			w := getAppendWriter([]byte("prefix "))
+			_, err := w.Write([]byte("data"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(w.buf)).To(Equal("prefix data"))
+			putAppendWriter(w)
+			Expect(w.buf).To(BeNil())
+		})
+	})
+})
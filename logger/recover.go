@@ -0,0 +1,89 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"fmt"
+	"os"
+)
+
+const (
+	// PanicProperty defines key of property containing panic value added to entries logged
+	// by Recover functions.
+	PanicProperty = "panic"
+	// panicMessage is the message of entries logged by Recover functions.
+	panicMessage = "Panic recovered."
+)
+
+// exit terminates the process. It is replaced in tests.
+var exit = os.Exit
+
+// logPanic logs recovered panic value with stack trace at CritLevel. Call context and stack
+// trace start at the panicking function. It must be called directly by a Recover function,
+// which is called by Go runtime while panicking. These frames are skipped by depth, not marked
+// with Helper, so capturing of call context of other logging calls stays cheap.
+func (l *Logger) logPanic(value interface{}) {
+	// Skip logPanic and Recover function. Frames of Go runtime are skipped by findCallerPC.
+	const depth = 2
+	e := l.newEntry().WithCaller(findCallerPC(depth, maxCallContextDepth)).IncDepth(depth).
+		WithProperty(PanicProperty, fmt.Sprint(value)).WithStack()
+	if err, ok := value.(error); ok {
+		e.WithError(err)
+	}
+	e.Critical(panicMessage)
+}
+
+// Recover recovers from panic and logs it with stack trace at CritLevel. Execution continues
+// after the deferred call. It must be called directly by defer statement:
+//
+//	defer log.Recover()
+func (l *Logger) Recover() {
+	if value := recover(); value != nil {
+		l.logPanic(value)
+	}
+}
+
+// RecoverRepanic recovers from panic, logs it like Recover does, flushes backends
+// and panics again with the same value. It must be called directly by defer statement.
+func (l *Logger) RecoverRepanic() {
+	if value := recover(); value != nil {
+		l.logPanic(value)
+		// Nothing can be done with failure of flushing while panicking.
+		_ = l.Flush()
+		panic(value)
+	}
+}
+
+// RecoverExit recovers from panic, logs it like Recover does, flushes backends and exits
+// the process with given status code. It must be called directly by defer statement.
+func (l *Logger) RecoverExit(code int) {
+	if value := recover(); value != nil {
+		l.logPanic(value)
+		// Nothing can be done with failure of flushing while exiting.
+		_ = l.Flush()
+		exit(code)
+	}
+}
+
+// Go runs f in a new goroutine. Panic in f is recovered and logged like with Recover,
+// so it does not crash the whole process.
+func (l *Logger) Go(f func()) {
+	go func() {
+		defer l.Recover()
+		f()
+	}()
+}
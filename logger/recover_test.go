@@ -0,0 +1,206 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"errors"
+	"os"
+	"runtime"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Recover", func() {
+	const thisFile = "recover_test.go"
+	var (
+		L *Logger
+		f *filterCollector
+		w *flushCounter
+	)
+
+	BeforeEach(func() {
+		L = NewLogger()
+		f = new(filterCollector)
+		w = new(flushCounter)
+		L.AddBackend("collector", Backend{
+			Filter:     f,
+			Serializer: NewSerializerJSON(),
+			Writer:     w,
+		})
+	})
+	AfterEach(func() {
+		exit = os.Exit
+	})
+
+	expectPanicLogged := func(value string, line int) *Entry {
+		entries := f.Entries()
+		Expect(entries).To(HaveLen(1))
+		e := entries[0]
+		Expect(e.Level).To(Equal(CritLevel))
+		Expect(e.Message).To(Equal(panicMessage))
+		Expect(e.Properties).To(HaveKeyWithValue(PanicProperty, value))
+		Expect(e.CallContext).NotTo(BeNil())
+		Expect(e.CallContext.File).To(Equal(thisFile))
+		Expect(e.CallContext.Line).To(Equal(line))
+		Expect(e.Stack).NotTo(BeEmpty())
+		Expect(e.Stack[0]).To(Equal(*e.CallContext))
+		return e
+	}
+
+	Describe("Logger", func() {
+		It("should log panic and continue", func() {
+			var line int
+			func() {
+				defer L.Recover()
+				_, _, line, _ = runtime.Caller(0)
+				panic("boom")
+			}()
+			expectPanicLogged("boom", line+1)
+			Expect(w.flushed).To(BeZero())
+		})
+		It("should not mark Recover functions as helpers", func() {
+			func() {
+				defer L.Recover()
+				panic("boom")
+			}()
+			Expect(isHelper(thisPackage + ".(*Logger).Recover")).To(BeFalse())
+			Expect(isHelper(thisPackage + ".(*Logger).logPanic")).To(BeFalse())
+		})
+		It("should log error panic value with error property", func() {
+			testError := errors.New("test error")
+			func() {
+				defer L.Recover()
+				panic(testError)
+			}()
+			e := f.Entries()[0]
+			Expect(e.Properties).To(HaveKeyWithValue(PanicProperty, testError.Error()))
+			Expect(e.Properties).To(HaveKeyWithValue(ErrorProperty, NewErrorValue(testError)))
+		})
+		It("should log runtime error panic at the faulting line", func() {
+			var line int
+			func() {
+				defer L.Recover()
+				var p *Entry
+				_, _, line, _ = runtime.Caller(0)
+				p.Level = ErrLevel
+			}()
+			e := f.Entries()[0]
+			Expect(e.CallContext.Line).To(Equal(line + 1))
+			Expect(e.Properties).To(HaveKey(ErrorProperty))
+		})
+		It("should do nothing without panic", func() {
+			func() {
+				defer L.Recover()
+			}()
+			Expect(f.Entries()).To(BeEmpty())
+		})
+		It("should log panic, flush backends and panic again", func() {
+			var line int
+			Expect(func() {
+				defer L.RecoverRepanic()
+				_, _, line, _ = runtime.Caller(0)
+				panic("boom")
+			}).To(PanicWith("boom"))
+			expectPanicLogged("boom", line+1)
+			Expect(w.flushed).To(Equal(1))
+		})
+		It("should log panic, flush backends and exit", func() {
+			code := -1
+			exit = func(c int) {
+				code = c
+			}
+			var line int
+			func() {
+				defer L.RecoverExit(3)
+				_, _, line, _ = runtime.Caller(0)
+				panic("boom")
+			}()
+			expectPanicLogged("boom", line+1)
+			Expect(w.flushed).To(Equal(1))
+			Expect(code).To(Equal(3))
+		})
+		It("should not exit without panic", func() {
+			exit = func(int) {
+				Fail("exit called")
+			}
+			func() {
+				defer L.RecoverExit(3)
+			}()
+		})
+		It("should recover panic in goroutine started with Go", func() {
+			done := make(chan struct{})
+			var line int
+			L.Go(func() {
+				defer close(done)
+				_, _, line, _ = runtime.Caller(0)
+				panic("boom")
+			})
+			<-done
+			Eventually(f.Entries).Should(HaveLen(1))
+			expectPanicLogged("boom", line+1)
+		})
+	})
+	Describe("default logger", func() {
+		var previous *Logger
+		BeforeEach(func() {
+			previous = defaultLogger
+			SetDefault(L)
+		})
+		AfterEach(func() {
+			SetDefault(previous)
+		})
+		It("should log panic and continue", func() {
+			var line int
+			func() {
+				defer Recover()
+				_, _, line, _ = runtime.Caller(0)
+				panic("boom")
+			}()
+			expectPanicLogged("boom", line+1)
+		})
+		It("should log panic, flush backends and panic again", func() {
+			var line int
+			Expect(func() {
+				defer RecoverRepanic()
+				_, _, line, _ = runtime.Caller(0)
+				panic("boom")
+			}).To(PanicWith("boom"))
+			expectPanicLogged("boom", line+1)
+			Expect(w.flushed).To(Equal(1))
+		})
+		It("should log panic, flush backends and exit", func() {
+			code := -1
+			exit = func(c int) {
+				code = c
+			}
+			func() {
+				defer RecoverExit(4)
+				panic("boom")
+			}()
+			Expect(f.Entries()).To(HaveLen(1))
+			Expect(w.flushed).To(Equal(1))
+			Expect(code).To(Equal(4))
+		})
+		It("should recover panic in goroutine started with Go", func() {
+			Go(func() {
+				panic("boom")
+			})
+			Eventually(f.Entries).Should(HaveLen(1))
+		})
+	})
+})
@@ -0,0 +1,298 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"net/rpc"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/SamsungSLAV/slav/logger/internal/caller"
+)
+
+const (
+	// ServiceMethodProperty defines key of field holding name of the called RPC method.
+	ServiceMethodProperty = "service_method"
+	// SeqProperty defines key of field holding sequence number of the RPC call.
+	SeqProperty = "seq"
+	// ArgsProperty defines key of field holding arguments of the RPC call.
+	ArgsProperty = "args"
+)
+
+// rpcPackage is the prefix of functions of net/rpc package, which are skipped while looking
+// for the caller of RPC method.
+const rpcPackage = "net/rpc."
+
+// rpcCall describes pending RPC call.
+type rpcCall struct {
+	// serviceMethod is the name of the called method.
+	serviceMethod string
+	// start is the time the call started.
+	start time.Time
+	// pc is the program counter of the caller used as call context.
+	pc uintptr
+}
+
+// rpcCalls holds pending RPC calls by their sequence numbers.
+type rpcCalls struct {
+	mutex sync.Mutex
+	calls map[uint64]rpcCall
+}
+
+// add adds pending call.
+func (c *rpcCalls) add(seq uint64, call rpcCall) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.calls == nil {
+		c.calls = make(map[uint64]rpcCall)
+	}
+	c.calls[seq] = call
+}
+
+// get returns pending call.
+func (c *rpcCalls) get(seq uint64) rpcCall {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.calls[seq]
+}
+
+// remove removes and returns pending call.
+func (c *rpcCalls) remove(seq uint64) (rpcCall, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	call, ok := c.calls[seq]
+	delete(c.calls, seq)
+	return call, ok
+}
+
+// removeAll removes and returns all pending calls.
+func (c *rpcCalls) removeAll() map[uint64]rpcCall {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	calls := c.calls
+	c.calls = nil
+	return calls
+}
+
+// entry returns a new entry of l with call context of the caller of the call. Server-side calls
+// have no caller, so their entries have no call context.
+func (call rpcCall) entry(l *Logger) *Entry {
+	if call.pc == 0 {
+		return l.newEntry().withoutCaller()
+	}
+	return l.newEntry().WithCaller(call.pc)
+}
+
+// logRPCCall logs finished call with level depending on err.
+func logRPCCall(l *Logger, level, errLevel Level, call rpcCall, seq uint64, err error) {
+	e := call.entry(l).WithFields(
+		String(ServiceMethodProperty, call.serviceMethod),
+		Any(SeqProperty, seq),
+		Duration(DurationProperty, time.Since(call.start)),
+	)
+	if err != nil {
+		e.WithError(err).Log(errLevel, call.serviceMethod+" failed")
+		return
+	}
+	e.Log(level, call.serviceMethod)
+}
+
+// logRPCArgs logs arguments of the call with DebugLevel.
+func logRPCArgs(l *Logger, call rpcCall, seq uint64, args interface{}) {
+	call.entry(l).WithFields(
+		String(ServiceMethodProperty, call.serviceMethod),
+		Any(SeqProperty, seq),
+		Any(ArgsProperty, args),
+	).Log(DebugLevel, call.serviceMethod+" called")
+}
+
+// responseError returns error of RPC response.
+func responseError(r *rpc.Response) error {
+	if r.Error == "" {
+		return nil
+	}
+	return rpc.ServerError(r.Error)
+}
+
+// RPCServerCodec is a rpc.ServerCodec logging every handled call with its service method,
+// sequence number, duration and error. Arguments of calls can be logged with DebugLevel.
+// Handled calls have no meaningful call site, so entries are logged without call context.
+type RPCServerCodec struct {
+	rpc.ServerCodec
+	// Logger logs calls.
+	Logger *Logger
+	// Level is the level of successful calls.
+	Level Level
+	// ErrorLevel is the level of failed calls.
+	ErrorLevel Level
+	// DumpArgs set to true makes arguments of calls logged with DebugLevel.
+	DumpArgs bool
+	// calls holds calls waiting for response.
+	calls rpcCalls
+	// seq is the sequence number of the call, which body is read next.
+	seq uint64
+}
+
+// NewRPCServerCodec creates and returns a new RPCServerCodec wrapping c and logging calls
+// to l. Successful calls are logged with InfoLevel and failed calls with ErrLevel.
+func NewRPCServerCodec(l *Logger, c rpc.ServerCodec) *RPCServerCodec {
+	return &RPCServerCodec{
+		ServerCodec: c,
+		Logger:      l,
+		Level:       InfoLevel,
+		ErrorLevel:  ErrLevel,
+	}
+}
+
+// ReadRequestHeader reads header of the request and starts timing the call. It implements
+// rpc.ServerCodec interface in RPCServerCodec.
+func (c *RPCServerCodec) ReadRequestHeader(r *rpc.Request) error {
+	err := c.ServerCodec.ReadRequestHeader(r)
+	if err == nil {
+		c.seq = r.Seq
+		c.calls.add(r.Seq, rpcCall{serviceMethod: r.ServiceMethod, start: time.Now()})
+	}
+	return err
+}
+
+// ReadRequestBody reads arguments of the call and logs them if DumpArgs is set. It implements
+// rpc.ServerCodec interface in RPCServerCodec.
+func (c *RPCServerCodec) ReadRequestBody(args interface{}) error {
+	err := c.ServerCodec.ReadRequestBody(args)
+	if err == nil && c.DumpArgs && args != nil {
+		logRPCArgs(c.Logger, c.calls.get(c.seq), c.seq, args)
+	}
+	return err
+}
+
+// WriteResponse writes response and logs the finished call. It implements rpc.ServerCodec
+// interface in RPCServerCodec.
+func (c *RPCServerCodec) WriteResponse(r *rpc.Response, reply interface{}) error {
+	err := c.ServerCodec.WriteResponse(r, reply)
+	call, ok := c.calls.remove(r.Seq)
+	if !ok {
+		call = rpcCall{serviceMethod: r.ServiceMethod, start: time.Now()}
+	}
+	if rerr := responseError(r); rerr != nil {
+		err = rerr
+	}
+	logRPCCall(c.Logger, c.Level, c.ErrorLevel, call, r.Seq, err)
+	return err
+}
+
+// RPCClientCodec is a rpc.ClientCodec logging every call with its service method, sequence
+// number, duration and error. Arguments of calls can be logged with DebugLevel. Call context
+// of entries is the caller of rpc.Client's methods.
+type RPCClientCodec struct {
+	rpc.ClientCodec
+	// Logger logs calls.
+	Logger *Logger
+	// Level is the level of successful calls.
+	Level Level
+	// ErrorLevel is the level of failed calls.
+	ErrorLevel Level
+	// DumpArgs set to true makes arguments of calls logged with DebugLevel.
+	DumpArgs bool
+	// calls holds calls waiting for response.
+	calls rpcCalls
+	// response is the header of the response, which body is read next.
+	response rpc.Response
+}
+
+// NewRPCClientCodec creates and returns a new RPCClientCodec wrapping c and logging calls
+// to l. Successful calls are logged with InfoLevel and failed calls with ErrLevel.
+func NewRPCClientCodec(l *Logger, c rpc.ClientCodec) *RPCClientCodec {
+	return &RPCClientCodec{
+		ClientCodec: c,
+		Logger:      l,
+		Level:       InfoLevel,
+		ErrorLevel:  ErrLevel,
+	}
+}
+
+// WriteRequest starts timing the call and writes the request. Failure of writing the request
+// is logged. It implements rpc.ClientCodec interface in RPCClientCodec.
+func (c *RPCClientCodec) WriteRequest(r *rpc.Request, args interface{}) error {
+	call := rpcCall{
+		serviceMethod: r.ServiceMethod,
+		start:         time.Now(),
+		pc:            caller.PC(rpcPackage),
+	}
+	if c.DumpArgs {
+		logRPCArgs(c.Logger, call, r.Seq, args)
+	}
+	c.calls.add(r.Seq, call)
+	err := c.ClientCodec.WriteRequest(r, args)
+	if err != nil {
+		c.calls.remove(r.Seq)
+		logRPCCall(c.Logger, c.Level, c.ErrorLevel, call, r.Seq, err)
+	}
+	return err
+}
+
+// ReadResponseHeader reads header of the response. If reading fails (e.g. connection is
+// closed), no more responses are read, so all pending calls are logged as failed with
+// the error. It implements rpc.ClientCodec interface in RPCClientCodec.
+func (c *RPCClientCodec) ReadResponseHeader(r *rpc.Response) error {
+	err := c.ClientCodec.ReadResponseHeader(r)
+	if err != nil {
+		c.failPending(err)
+		return err
+	}
+	c.response = *r
+	return nil
+}
+
+// failPending logs all pending calls as failed with err in order of their sequence numbers.
+func (c *RPCClientCodec) failPending(err error) {
+	calls := c.calls.removeAll()
+	seqs := make([]uint64, 0, len(calls))
+	for seq := range calls {
+		seqs = append(seqs, seq)
+	}
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+	for _, seq := range seqs {
+		logRPCCall(c.Logger, c.Level, c.ErrorLevel, calls[seq], seq, err)
+	}
+}
+
+// ReadResponseBody reads reply and logs the finished call. It implements rpc.ClientCodec
+// interface in RPCClientCodec.
+func (c *RPCClientCodec) ReadResponseBody(reply interface{}) error {
+	err := c.ClientCodec.ReadResponseBody(reply)
+	call, ok := c.calls.remove(c.response.Seq)
+	if !ok {
+		return err
+	}
+	callErr := err
+	if rerr := responseError(&c.response); rerr != nil {
+		callErr = rerr
+	}
+	logRPCCall(c.Logger, c.Level, c.ErrorLevel, call, c.response.Seq, callErr)
+	return err
+}
+
+// RPCServerCodec returns a new RPCServerCodec wrapping c.
+func (l *Logger) RPCServerCodec(c rpc.ServerCodec) *RPCServerCodec {
+	return NewRPCServerCodec(l, c)
+}
+
+// RPCClientCodec returns a new RPCClientCodec wrapping c.
+func (l *Logger) RPCClientCodec(c rpc.ClientCodec) *RPCClientCodec {
+	return NewRPCClientCodec(l, c)
+}
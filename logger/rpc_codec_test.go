@@ -0,0 +1,191 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"runtime"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// rpcArith is a RPC service used in tests.
+type rpcArith struct{}
+
+func (rpcArith) Add(args *[2]int, reply *int) error {
+	*reply = args[0] + args[1]
+	return nil
+}
+
+func (rpcArith) Fail(_ *int, _ *int) error {
+	return errors.New("test error")
+}
+
+var _ = Describe("RPC codecs", func() {
+	const thisFile = "rpc_codec_test.go"
+	var (
+		serverLogger, clientLogger   *Logger
+		serverEntries, clientEntries *filterCollector
+		serverCodec                  *RPCServerCodec
+		clientCodec                  *RPCClientCodec
+		client                       *rpc.Client
+	)
+
+	newCollectingLogger := func() (*Logger, *filterCollector) {
+		l := NewLogger()
+		l.SetThreshold(DebugLevel)
+		f := new(filterCollector)
+		l.AddBackend("collector", Backend{
+			Filter:     f,
+			Serializer: NewSerializerJSON(),
+			Writer:     new(writerCollector),
+		})
+		return l, f
+	}
+	value := func(e *Entry, key string) interface{} {
+		v, _ := e.property(key)
+		return v
+	}
+
+	BeforeEach(func() {
+		serverLogger, serverEntries = newCollectingLogger()
+		clientLogger, clientEntries = newCollectingLogger()
+		server := rpc.NewServer()
+		Expect(server.RegisterName("Arith", rpcArith{})).To(Succeed())
+		serverConn, clientConn := net.Pipe()
+		serverCodec = serverLogger.RPCServerCodec(jsonrpc.NewServerCodec(serverConn))
+		go server.ServeCodec(serverCodec)
+		clientCodec = clientLogger.RPCClientCodec(jsonrpc.NewClientCodec(clientConn))
+		client = rpc.NewClientWithCodec(clientCodec)
+	})
+
+	AfterEach(func() {
+		Expect(client.Close()).To(Succeed())
+	})
+
+	It("should create new objects with default configuration", func() {
+		for _, c := range []struct {
+			logger          *Logger
+			level, errLevel Level
+			dumpArgs        bool
+		}{
+			{serverCodec.Logger, serverCodec.Level, serverCodec.ErrorLevel, serverCodec.DumpArgs},
+			{clientCodec.Logger, clientCodec.Level, clientCodec.ErrorLevel, clientCodec.DumpArgs},
+		} {
+			Expect(c.logger).NotTo(BeNil())
+			Expect(c.level).To(Equal(InfoLevel))
+			Expect(c.errLevel).To(Equal(ErrLevel))
+			Expect(c.dumpArgs).To(BeFalse())
+		}
+	})
+	It("should log successful calls on both sides", func() {
+		var reply int
+		_, _, line, _ := runtime.Caller(0)
+		Expect(client.Call("Arith.Add", &[2]int{1, 2}, &reply)).To(Succeed())
+		Expect(reply).To(Equal(3))
+
+		entries := clientEntries.Entries()
+		Expect(entries).To(HaveLen(1))
+		e := entries[0]
+		Expect(e.Level).To(Equal(InfoLevel))
+		Expect(e.Message).To(Equal("Arith.Add"))
+		Expect(value(e, ServiceMethodProperty)).To(Equal("Arith.Add"))
+		Expect(value(e, SeqProperty)).To(Equal(uint64(0)))
+		Expect(value(e, DurationProperty)).To(BeNumerically(">", 0))
+		Expect(e.CallContext.File).To(Equal(thisFile))
+		Expect(e.CallContext.Line).To(Equal(line + 1))
+
+		Eventually(serverEntries.Entries).Should(HaveLen(1))
+		e = serverEntries.Entries()[0]
+		Expect(e.Level).To(Equal(InfoLevel))
+		Expect(e.Message).To(Equal("Arith.Add"))
+		Expect(value(e, ServiceMethodProperty)).To(Equal("Arith.Add"))
+		Expect(e.CallContext).To(BeNil())
+	})
+	It("should log failed calls on both sides", func() {
+		var reply int
+		err := client.Call("Arith.Fail", 1, &reply)
+		Expect(err).To(Equal(rpc.ServerError("test error")))
+
+		e := clientEntries.Entries()[0]
+		Expect(e.Level).To(Equal(ErrLevel))
+		Expect(e.Message).To(Equal("Arith.Fail failed"))
+		Expect(e.Properties).To(Equal(Properties{ErrorProperty: NewErrorValue(err)}))
+
+		Eventually(serverEntries.Entries).Should(HaveLen(1))
+		e = serverEntries.Entries()[0]
+		Expect(e.Level).To(Equal(ErrLevel))
+		Expect(e.Properties).To(Equal(Properties{ErrorProperty: NewErrorValue(err)}))
+	})
+	It("should log unknown methods", func() {
+		var reply int
+		Expect(client.Call("Arith.Unknown", 1, &reply)).NotTo(Succeed())
+		Expect(clientEntries.Entries()[0].Message).To(Equal("Arith.Unknown failed"))
+		Eventually(serverEntries.Entries).Should(HaveLen(1))
+	})
+	It("should dump arguments with debug level", func() {
+		serverCodec.DumpArgs = true
+		clientCodec.DumpArgs = true
+		var reply int
+		Expect(client.Call("Arith.Add", &[2]int{1, 2}, &reply)).To(Succeed())
+
+		entries := clientEntries.Entries()
+		Expect(entries).To(HaveLen(2))
+		Expect(entries[0].Level).To(Equal(DebugLevel))
+		Expect(entries[0].Message).To(Equal("Arith.Add called"))
+		Expect(value(entries[0], ArgsProperty)).To(Equal(&[2]int{1, 2}))
+
+		Eventually(serverEntries.Entries).Should(HaveLen(2))
+		e := serverEntries.Entries()[0]
+		Expect(e.Level).To(Equal(DebugLevel))
+		Expect(value(e, ServiceMethodProperty)).To(Equal("Arith.Add"))
+		Expect(value(e, ArgsProperty)).To(Equal(&[2]int{1, 2}))
+		Expect(e.CallContext).To(BeNil())
+	})
+	It("should log failure of writing request", func() {
+		Expect(clientCodec.ClientCodec.Close()).To(Succeed())
+		var reply int
+		Expect(client.Call("Arith.Add", &[2]int{1, 2}, &reply)).NotTo(Succeed())
+		Eventually(clientEntries.Entries).ShouldNot(BeEmpty())
+		Expect(clientEntries.Entries()[0].Level).To(Equal(ErrLevel))
+	})
+	It("should log pending calls when connection is closed", func() {
+		serverConn, clientConn := net.Pipe()
+		go func() {
+			var request interface{}
+			_ = json.NewDecoder(serverConn).Decode(&request)
+			_, _ = serverConn.Write([]byte(`{"id":`))
+			_ = serverConn.Close()
+		}()
+		codec := clientLogger.RPCClientCodec(jsonrpc.NewClientCodec(clientConn))
+		c := rpc.NewClientWithCodec(codec)
+		var reply int
+		Expect(c.Call("Arith.Add", &[2]int{1, 2}, &reply)).NotTo(Succeed())
+
+		entries := clientEntries.Entries()
+		Expect(entries).To(HaveLen(1))
+		Expect(entries[0].Level).To(Equal(ErrLevel))
+		Expect(entries[0].Message).To(Equal("Arith.Add failed"))
+		Expect(value(entries[0], ErrorProperty)).NotTo(BeNil())
+		Expect(codec.calls.calls).To(BeEmpty())
+	})
+})
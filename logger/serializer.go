@@ -18,7 +18,29 @@ package logger
 
 // Serializer converts entry into raw bytes slice.
 // After that it is ready to be passed to Writer.
+//
+// Entries passed to it must not be retained, see Entry.Clone.
 type Serializer interface {
 	// Serialize converts entry to byte slice.
 	Serialize(*Entry) ([]byte, error)
 }
+
+// AppendSerializer is implemented by Serializers able to append serialized entry to a buffer
+// provided by the caller. Backend uses it with pooled buffers to avoid allocations.
+type AppendSerializer interface {
+	// AppendSerialize appends serialized entry to dst and returns the extended buffer.
+	AppendSerialize(dst []byte, entry *Entry) ([]byte, error)
+}
+
+// serializeCopy serializes entry with s into a pooled buffer and returns a copy of the result
+// of exact size.
+func serializeCopy(s AppendSerializer, entry *Entry) ([]byte, error) {
+	pooled := getBuffer()
+	defer putBuffer(pooled)
+	buf, err := s.AppendSerialize(*pooled, entry)
+	if err != nil {
+		return nil, err
+	}
+	*pooled = buf
+	return append([]byte(nil), buf...), nil
+}
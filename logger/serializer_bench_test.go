@@ -0,0 +1,86 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"testing"
+	"time"
+)
+
+// newBenchEntry creates an Entry with all fields set.
+func newBenchEntry() *Entry {
+	return &Entry{
+		Level:     ErrLevel,
+		Message:   "Benchmark message.",
+		Timestamp: time.Now(),
+		CallContext: &CallContext{
+			Path:     "/path/to/",
+			File:     "file.go",
+			Line:     123,
+			Package:  "package",
+			Type:     "type",
+			Function: "function",
+		},
+		Properties: Properties{
+			"dryad": "rpi3",
+			"job":   17,
+		},
+	}
+}
+
+// benchmarkSerialize benchmarks Serialize method of given Serializer.
+func benchmarkSerialize(b *testing.B, s Serializer) {
+	e := newBenchEntry()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.Serialize(e); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// benchmarkAppendSerialize benchmarks AppendSerialize method of given AppendSerializer
+// reusing a single buffer.
+func benchmarkAppendSerialize(b *testing.B, s AppendSerializer) {
+	e := newBenchEntry()
+	buf := make([]byte, 0, defaultBufferSize)
+	var err error
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if buf, err = s.AppendSerialize(buf[:0], e); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSerializerJSONSerialize(b *testing.B) {
+	benchmarkSerialize(b, NewSerializerJSON())
+}
+
+func BenchmarkSerializerJSONAppendSerialize(b *testing.B) {
+	benchmarkAppendSerialize(b, NewSerializerJSON())
+}
+
+func BenchmarkSerializerTextSerialize(b *testing.B) {
+	benchmarkSerialize(b, NewSerializerText())
+}
+
+func BenchmarkSerializerTextAppendSerialize(b *testing.B) {
+	benchmarkAppendSerialize(b, NewSerializerText())
+}
@@ -18,7 +18,10 @@ package logger
 
 import (
 	"encoding/json"
+	"sort"
+	"strconv"
 	"time"
+	"unicode/utf8"
 )
 
 const (
@@ -26,15 +29,8 @@ const (
 	DefaultSerializerJSONTimestampFormat = time.RFC3339
 )
 
-type serializerJSONRecord struct {
-	Level       string       `json:"level"`
-	Message     string       `json:"message"`
-	Timestamp   string       `json:"timestamp"`
-	CallContext *CallContext `json:"callcontext,omitempty"`
-	Properties  Properties   `json:"properties,omitempty"`
-}
-
-// SerializerJSON serializes entry to JSON format.
+// SerializerJSON serializes entry to JSON format. Properties with TraceIDProperty
+// and SpanIDProperty keys are placed in top level trace_id and span_id fields.
 type SerializerJSON struct {
 	// TimestampFormat defines format for displaying date and time.
 	// See https://godoc.org/time#Time.Format description for details.
@@ -48,18 +44,197 @@ func NewSerializerJSON() *SerializerJSON {
 	}
 }
 
-// Serialize marshals entry to JSON. It implements loggers' Serializer interface.
-func (s *SerializerJSON) Serialize(entry *Entry) ([]byte, error) {
+// appendRecord appends entry encoded as JSON object to buf.
+func (s *SerializerJSON) appendRecord(buf []byte, entry *Entry) ([]byte, error) {
 	format := s.TimestampFormat
 	if format == "" {
 		format = DefaultSerializerJSONTimestampFormat
 	}
-	record := serializerJSONRecord{
-		Level:       entry.Level.String(),
-		Message:     entry.Message,
-		Timestamp:   entry.Timestamp.UTC().Format(format),
-		CallContext: entry.CallContext,
-		Properties:  entry.Properties,
+	buf = appendJSONString(append(buf, `{"level":`...), entry.Level.String())
+	buf = appendJSONString(append(buf, `,"message":`...), entry.Message)
+	buf = appendJSONTime(append(buf, `,"timestamp":`...), entry.Timestamp.UTC(), format)
+	p := &serializerJSONProperties{fields: entry.Fields, properties: entry.Properties}
+	buf, err := p.appendJSONTrace(buf, entry)
+	if err != nil {
+		return buf, err
+	}
+	if entry.CallContext != nil {
+		buf = appendJSONCallContext(append(buf, `,"callcontext":`...), entry.CallContext)
+	}
+	if !p.empty() {
+		if buf, err = p.appendJSON(append(buf, `,"properties":`...)); err != nil {
+			return buf, err
+		}
+	}
+	if len(entry.Stack) > 0 {
+		buf = append(buf, `,"stack":[`...)
+		for i := range entry.Stack {
+			buf = appendJSONCallContext(appendJSONSeparator(buf), &entry.Stack[i])
+		}
+		buf = append(buf, ']')
+	}
+	return append(buf, '}'), nil
+}
+
+// appendJSONTime appends t formatted with given layout as JSON string to buf.
+func appendJSONTime(buf []byte, t time.Time, layout string) []byte {
+	start := len(buf)
+	buf = t.AppendFormat(append(buf, '"'), layout)
+	for _, b := range buf[start+1:] {
+		if b >= utf8.RuneSelf || !jsonSafe[b] {
+			// Rare case of layout with characters needing escaping.
+			return appendJSONString(buf[:start], string(buf[start+1:]))
+		}
+	}
+	return append(buf, '"')
+}
+
+// appendJSONCallContext appends ctx encoded as JSON object to buf.
+func appendJSONCallContext(buf []byte, ctx *CallContext) []byte {
+	buf = appendJSONString(append(buf, `{"path":`...), ctx.Path)
+	buf = appendJSONString(append(buf, `,"file":`...), ctx.File)
+	buf = strconv.AppendInt(append(buf, `,"line":`...), int64(ctx.Line), 10)
+	buf = appendJSONString(append(buf, `,"package":`...), ctx.Package)
+	if ctx.Type != "" {
+		buf = appendJSONString(append(buf, `,"type":`...), ctx.Type)
+	}
+	buf = appendJSONString(append(buf, `,"function":`...), ctx.Function)
+	return append(buf, '}')
+}
+
+// appendJSONValue appends v encoded as JSON to buf. Common types are encoded directly,
+// other ones are marshalled with encoding/json.
+func appendJSONValue(buf []byte, v interface{}) ([]byte, error) {
+	switch t := v.(type) {
+	case string:
+		return appendJSONString(buf, t), nil
+	case int:
+		return strconv.AppendInt(buf, int64(t), 10), nil
+	case int64:
+		return strconv.AppendInt(buf, t, 10), nil
+	case uint64:
+		return strconv.AppendUint(buf, t, 10), nil
+	case bool:
+		return strconv.AppendBool(buf, t), nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return buf, err
+	}
+	return append(buf, data...), nil
+}
+
+// serializerJSONProperties marshals fields in order in which they were added followed by
+// properties sorted by key. Properties shadowed by fields with the same key are omitted.
+type serializerJSONProperties struct {
+	fields     []Field
+	properties Properties
+	// omitTrace set to true makes trace and span IDs omitted.
+	omitTrace bool
+}
+
+// omitted returns true if field or property with given key is not marshalled.
+func (p *serializerJSONProperties) omitted(key string) bool {
+	return p.omitTrace && (key == TraceIDProperty || key == SpanIDProperty)
+}
+
+// empty returns true if there are no fields or properties to marshal.
+func (p *serializerJSONProperties) empty() bool {
+	for i := range p.fields {
+		if !p.omitted(p.fields[i].Key) {
+			return false
+		}
+	}
+	for k := range p.properties {
+		if !p.omitted(k) {
+			return false
+		}
+	}
+	return true
+}
+
+// appendJSONTrace appends trace and span IDs of entry as top level members of JSON object
+// to buf and marks them as omitted from properties.
+func (p *serializerJSONProperties) appendJSONTrace(buf []byte, entry *Entry) ([]byte, error) {
+	var err error
+	for _, k := range []string{TraceIDProperty, SpanIDProperty} {
+		if v, ok := entry.property(k); ok && v != nil {
+			buf = appendJSONString(append(buf, ','), k)
+			if buf, err = appendJSONValue(append(buf, ':'), v); err != nil {
+				return buf, err
+			}
+			p.omitTrace = true
+		}
+	}
+	return buf, nil
+}
+
+// appendJSON appends fields and properties encoded as JSON object to buf.
+func (p *serializerJSONProperties) appendJSON(buf []byte) ([]byte, error) {
+	buf = append(buf, '{')
+	var err error
+	for _, f := range p.fields {
+		if p.omitted(f.Key) {
+			continue
+		}
+		buf = append(appendJSONString(appendJSONSeparator(buf), f.Key), ':')
+		if buf, err = f.appendJSON(buf); err != nil {
+			return buf, err
+		}
+	}
+	// Keys of a few properties are kept on stack.
+	var small [8]string
+	for _, k := range p.keys(small[:0]) {
+		buf = append(appendJSONString(appendJSONSeparator(buf), k), ':')
+		if buf, err = appendJSONValue(buf, p.properties[k]); err != nil {
+			return buf, err
+		}
+	}
+	return append(buf, '}'), nil
+}
+
+// keys appends sorted keys of properties which are not shadowed nor omitted to keys.
+func (p *serializerJSONProperties) keys(keys []string) []string {
+	for k := range p.properties {
+		if isShadowed(p.fields, k) || p.omitted(k) {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// appendJSONSeparator appends comma to buf, unless it ends with opening brace or bracket.
+func appendJSONSeparator(buf []byte) []byte {
+	if c := buf[len(buf)-1]; c == '{' || c == '[' {
+		return buf
+	}
+	return append(buf, ',')
+}
+
+// isShadowed returns true if property with given key is shadowed by one of fields.
+func isShadowed(fields []Field, key string) bool {
+	for i := range fields {
+		if fields[i].Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Serialize marshals entry to JSON. It implements loggers' Serializer interface.
+func (s *SerializerJSON) Serialize(entry *Entry) ([]byte, error) {
+	return serializeCopy(s, entry)
+}
+
+// AppendSerialize appends entry marshalled to JSON to dst. Entry is encoded directly into dst,
+// so serialization into a reused buffer does not allocate memory for common values.
+// It implements AppendSerializer interface in SerializerJSON.
+func (s *SerializerJSON) AppendSerialize(dst []byte, entry *Entry) ([]byte, error) {
+	buf, err := s.appendRecord(dst, entry)
+	if err != nil {
+		return dst, err
 	}
-	return json.Marshal(record)
+	return buf, nil
 }
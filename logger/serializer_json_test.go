@@ -19,6 +19,7 @@ package logger
 import (
 	"errors"
 	"math"
+	"testing"
 	"time"
 
 	. "github.com/onsi/ginkgo"
@@ -91,9 +92,57 @@ var _ = Describe("SerializerJSON", func() {
 			expected := []byte(`{"level":"error","message":"message","timestamp":` +
 				`"2009-02-13T23:31:30Z","callcontext":{"path":"somePath","file":` +
 				`"someFile","line":1234567,"package":"somePackage","type":"someType",` +
-				`"function":"someFunction"},"properties":{"error":"test error"}}`)
+				`"function":"someFunction"},"properties":{"error":{"message":"test error",` +
+				`"type":"*errors.errorString"}}}`)
 			Expect(buf).To(Equal(expected))
 		})
+		It("should serialize fields in order followed by properties", func() {
+			e.CallContext = nil
+			e.WithProperties(Properties{"name": "Alice", "city": "Warsaw"})
+			e.WithFields(
+				String("name", "Bob <b>"),
+				Int("age", 42),
+				Bool("male", true),
+				Duration("took", 1500*time.Millisecond),
+				Time("at", time.Unix(1234567890, 5).UTC()),
+				Err(errors.New("test error")),
+				Any("skills", Properties{"coding": 7}),
+			)
+			buf, err := s.Serialize(e)
+			Expect(err).NotTo(HaveOccurred())
+			expected := []byte(`{"level":"error","message":"message","timestamp":` +
+				`"2009-02-13T23:31:30Z","properties":{"name":"Bob \u003cb\u003e","age":42,` +
+				`"male":true,"took":"1.5s","at":"2009-02-13T23:31:30.000000005Z",` +
+				`"error":{"message":"test error","type":"*errors.errorString"},` +
+				`"skills":{"coding":7},"city":"Warsaw"}}`)
+			Expect(buf).To(Equal(expected))
+		})
+		It("should place trace and span IDs in top level fields", func() {
+			e.CallContext = nil
+			e.WithProperties(Properties{TraceIDProperty: "t", "name": "Alice"})
+			e.WithFields(String(SpanIDProperty, "s"), Int("age", 42))
+			buf, err := s.Serialize(e)
+			Expect(err).NotTo(HaveOccurred())
+			expected := []byte(`{"level":"error","message":"message","timestamp":` +
+				`"2009-02-13T23:31:30Z","trace_id":"t","span_id":"s",` +
+				`"properties":{"age":42,"name":"Alice"}}`)
+			Expect(buf).To(Equal(expected))
+		})
+		It("should omit properties if there are only trace and span IDs", func() {
+			e.CallContext = nil
+			e.WithProperties(Properties{TraceIDProperty: "t", SpanIDProperty: "s"})
+			buf, err := s.Serialize(e)
+			Expect(err).NotTo(HaveOccurred())
+			expected := []byte(`{"level":"error","message":"message","timestamp":` +
+				`"2009-02-13T23:31:30Z","trace_id":"t","span_id":"s"}`)
+			Expect(buf).To(Equal(expected))
+		})
+		It("should return error if serialization of field is not possible", func() {
+			e.WithFields(Any("power", math.Inf(1)))
+			buf, err := s.Serialize(e)
+			Expect(err).To(HaveOccurred())
+			Expect(buf).To(BeNil())
+		})
 		It("should return error if serialization is not possible", func() {
 			e.WithProperties(Properties{
 				"power": math.Inf(1),
@@ -139,5 +188,43 @@ var _ = Describe("SerializerJSON", func() {
 				`"2009-02-13T23:31:30Z"}`)
 			Expect(buf).To(Equal(expected))
 		})
+		It("should serialize stack trace as array of frames", func() {
+			e.CallContext = nil
+			e.Stack = []CallContext{
+				{Path: "/p/", File: "f.go", Line: 12, Package: "a/b", Function: "g"},
+				{Path: "/p/", File: "h.go", Line: 3, Package: "a/b", Type: "t", Function: "u"},
+			}
+			buf, err := s.Serialize(e)
+			Expect(err).NotTo(HaveOccurred())
+			expected := []byte(`{"level":"error","message":"message","timestamp":` +
+				`"2009-02-13T23:31:30Z","stack":[{"path":"/p/","file":"f.go","line":12,` +
+				`"package":"a/b","function":"g"},{"path":"/p/","file":"h.go","line":3,` +
+				`"package":"a/b","type":"t","function":"u"}]}`)
+			Expect(buf).To(Equal(expected))
+		})
+	})
+	Describe("AppendSerialize", func() {
+		It("should append serialized message to given buffer", func() {
+			buf, err := s.AppendSerialize([]byte("prefix "), e)
+			Expect(err).NotTo(HaveOccurred())
+			expected, err := s.Serialize(e)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(buf).To(Equal(append([]byte("prefix "), expected...)))
+		})
+		It("should return unchanged buffer if serialization is not possible", func() {
+			e.WithProperty("power", math.Inf(1))
+			buf, err := s.AppendSerialize([]byte("prefix "), e)
+			Expect(err.Error()).To(Equal("json: unsupported value: +Inf"))
+			Expect(buf).To(Equal([]byte("prefix ")))
+		})
+		It("should not allocate memory if buffer is big enough", func() {
+			e.WithProperties(Properties{"name": "Alice", "age": 37}).
+				WithFields(String("city", "Warsaw"), Int("floor", 3))
+			buf := make([]byte, 0, defaultBufferSize)
+			allocs := testing.AllocsPerRun(100, func() {
+				_, _ = s.AppendSerialize(buf[:0], e)
+			})
+			Expect(allocs).To(BeZero())
+		})
 	})
 })
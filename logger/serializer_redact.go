@@ -0,0 +1,266 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// RedactMode defines how secret values are replaced by SerializerRedact.
+type RedactMode uint8
+
+const (
+	// RedactModeMask - secret is replaced with Mask.
+	RedactModeMask RedactMode = iota
+	// RedactModeHash - secret is replaced with a short keyed hash (HMAC-SHA256) of its value,
+	// so entries containing the same secret can be correlated.
+	RedactModeHash
+	// RedactModeTruncate - only TruncateLength first characters of secret are kept
+	// and followed by Mask.
+	RedactModeTruncate
+)
+
+const (
+	// DefaultRedactMask is the default replacement of secret values.
+	DefaultRedactMask = "***"
+	// redactHashPrefix precedes hashes of secret values.
+	redactHashPrefix = "hmac-sha256:"
+	// redactHashSize is the number of bytes of hash written in RedactModeHash.
+	redactHashSize = 8
+	// redactHashKeySize is the number of bytes of hash key generated by NewSerializerRedact.
+	redactHashKeySize = 32
+	// maxRedactDepth limits depth of nested values redacted by SerializerRedact.
+	maxRedactDepth = 32
+)
+
+// Redactor is implemented by property and field values, which know how to hide their secrets.
+// SerializerRedact logs value returned by Redact instead of the original one.
+type Redactor interface {
+	// Redact returns value safe to be logged.
+	Redact() interface{}
+}
+
+// SerializerRedact hides secrets before passing entries to the wrapped Serializer. It replaces
+// values of properties and fields with matching keys, fragments of messages and string values
+// matching patterns and values implementing Redactor interface. Nested Properties, maps
+// with string keys and error values (see ErrorValue) are redacted recursively. Different
+// backends can use different redaction rules, e.g. a local debug file can keep more than logs
+// shipped to a remote server.
+type SerializerRedact struct {
+	// Serializer is the wrapped serializer.
+	Serializer Serializer
+	// Keys contains patterns of keys of properties and fields to be redacted. Patterns use
+	// filepath.Match syntax (e.g. "*token*") and are matched case-insensitively.
+	Keys []string
+	// Patterns contains regular expressions matching secrets in messages, string values
+	// and error messages.
+	Patterns []*regexp.Regexp
+	// Mode defines how secrets are replaced.
+	Mode RedactMode
+	// Mask replaces secrets in RedactModeMask and RedactModeTruncate modes.
+	Mask string
+	// TruncateLength is the number of characters kept in RedactModeTruncate mode.
+	TruncateLength int
+	// HashKey is the key of HMAC used in RedactModeHash mode. Hashes can be correlated only
+	// if they are computed with the same key. If it is empty, hashes of low-entropy secrets
+	// (e.g. short passwords or numbers) can be easily reversed by brute force.
+	HashKey []byte
+}
+
+// NewSerializerRedact creates and returns a new SerializerRedact wrapping s, which masks
+// properties and fields with keys matching given patterns. Random HashKey is generated,
+// so hashes can be correlated only within the process.
+func NewSerializerRedact(s Serializer, keys ...string) *SerializerRedact {
+	key := make([]byte, redactHashKeySize)
+	// crypto/rand.Read never fails on supported platforms.
+	_, _ = rand.Read(key)
+	return &SerializerRedact{
+		Serializer: s,
+		Keys:       keys,
+		Mode:       RedactModeMask,
+		Mask:       DefaultRedactMask,
+		HashKey:    key,
+	}
+}
+
+// Serialize redacts entry and serializes it with the wrapped Serializer.
+// It implements Serializer interface in SerializerRedact.
+func (s *SerializerRedact) Serialize(entry *Entry) ([]byte, error) {
+	return s.Serializer.Serialize(s.redact(entry))
+}
+
+// AppendSerialize redacts entry and appends it serialized with the wrapped Serializer to dst.
+// It implements AppendSerializer interface in SerializerRedact.
+func (s *SerializerRedact) AppendSerialize(dst []byte, entry *Entry) ([]byte, error) {
+	entry = s.redact(entry)
+	if as, ok := s.Serializer.(AppendSerializer); ok {
+		return as.AppendSerialize(dst, entry)
+	}
+	buf, err := s.Serializer.Serialize(entry)
+	if err != nil {
+		return dst, err
+	}
+	return append(dst, buf...), nil
+}
+
+// UsesCallContext returns true if the wrapped Serializer uses call context.
+// It implements CallContextUser interface in SerializerRedact.
+func (s *SerializerRedact) UsesCallContext() bool {
+	return usesCallContext(s.Serializer)
+}
+
+// redact returns copy of entry with secrets replaced. Entry itself is not modified as it is
+// shared with other backends.
+func (s *SerializerRedact) redact(entry *Entry) *Entry {
+	if entry == nil {
+		return nil
+	}
+	c := entry.clone()
+	c.Message = s.redactString(c.Message)
+	for k, v := range c.Properties {
+		c.Properties[k] = s.redactValue(k, v, maxRedactDepth)
+	}
+	for i, f := range c.Fields {
+		c.Fields[i] = s.redactField(f)
+	}
+	return c
+}
+
+// redactString returns s with fragments matching Patterns replaced.
+func (s *SerializerRedact) redactString(str string) string {
+	for _, re := range s.Patterns {
+		str = re.ReplaceAllStringFunc(str, s.replace)
+	}
+	return str
+}
+
+// redactValue returns value of property with given key safe to be logged. Nested values are
+// redacted up to given depth.
+func (s *SerializerRedact) redactValue(key string, value interface{}, depth int) interface{} {
+	if r, ok := value.(Redactor); ok {
+		value = r.Redact()
+	}
+	if s.matchKey(key) {
+		return s.replace(fmt.Sprint(value))
+	}
+	if depth <= 0 {
+		return value
+	}
+	switch v := value.(type) {
+	case string:
+		return s.redactString(v)
+	case Properties:
+		return s.redactProperties(v, depth-1)
+	case map[string]interface{}:
+		return map[string]interface{}(s.redactProperties(v, depth-1))
+	case *ErrorValue:
+		return s.redactError(v, depth-1)
+	case error:
+		return s.redactError(NewErrorValue(v), depth-1)
+	}
+	return value
+}
+
+// redactProperties returns copy of properties with values redacted up to given depth.
+func (s *SerializerRedact) redactProperties(props Properties, depth int) Properties {
+	if props == nil {
+		return nil
+	}
+	ret := make(Properties, len(props))
+	for k, v := range props {
+		ret[k] = s.redactValue(k, v, depth)
+	}
+	return ret
+}
+
+// redactError returns copy of error value with message, properties and causes redacted up
+// to given depth.
+func (s *SerializerRedact) redactError(v *ErrorValue, depth int) *ErrorValue {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	c.Message = s.redactString(v.Message)
+	c.Properties = s.redactProperties(v.Properties, depth)
+	if depth <= 0 {
+		c.Causes = nil
+		return &c
+	}
+	c.Causes = make([]*ErrorValue, len(v.Causes))
+	for i, cause := range v.Causes {
+		c.Causes[i] = s.redactError(cause, depth-1)
+	}
+	return &c
+}
+
+// redactField returns field safe to be logged.
+func (s *SerializerRedact) redactField(f Field) Field {
+	if r, ok := f.Interface.(Redactor); ok && f.Type == FieldTypeAny {
+		f = Any(f.Key, r.Redact())
+	}
+	if s.matchKey(f.Key) {
+		return String(f.Key, s.replace(f.text()))
+	}
+	switch f.Type {
+	case FieldTypeString:
+		f.String = s.redactString(f.String)
+	case FieldTypeAny, FieldTypeError:
+		f = Any(f.Key, s.redactValue(f.Key, f.Value(), maxRedactDepth))
+	}
+	return f
+}
+
+// matchKey verifies if key matches any of redacted keys patterns.
+func (s *SerializerRedact) matchKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, pattern := range s.Keys {
+		if ok, _ := filepath.Match(strings.ToLower(pattern), key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// replace returns replacement of secret according to Mode.
+func (s *SerializerRedact) replace(secret string) string {
+	switch s.Mode {
+	case RedactModeHash:
+		mac := hmac.New(sha256.New, s.HashKey)
+		// Writing to hash never fails.
+		_, _ = mac.Write([]byte(secret))
+		return redactHashPrefix + hex.EncodeToString(mac.Sum(nil)[:redactHashSize])
+	case RedactModeTruncate:
+		runes := []rune(secret)
+		if n := s.TruncateLength; len(runes) > n {
+			if n < 0 {
+				n = 0
+			}
+			runes = runes[:n]
+		}
+		return string(runes) + s.Mask
+	case RedactModeMask:
+	default:
+	}
+	return s.Mask
+}
@@ -0,0 +1,202 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	gomock "github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	T "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+// credentials is a property value hiding its password.
+type credentials struct {
+	user     string
+	password string
+}
+
+func (c credentials) Redact() interface{} {
+	return c.user + ":" + DefaultRedactMask
+}
+
+var _ = Describe("SerializerRedact", func() {
+	var (
+		s *SerializerRedact
+		e *Entry
+	)
+
+	BeforeEach(func() {
+		s = NewSerializerRedact(newPlainSerializerText(), "*token*", "Password")
+		s.Patterns = []*regexp.Regexp{regexp.MustCompile(`secret-[0-9]+`)}
+		e = &Entry{
+			Level:   InfoLevel,
+			Message: "using secret-1234 and secret-99",
+			Properties: Properties{
+				"AccessToken": "abcdef",
+				"password":    42,
+				"user":        "alice",
+				"login":       credentials{"bob", "hunter2"},
+			},
+			Fields: []Field{
+				String("refresh_token", "ghijkl"),
+				Int("count", 3),
+				Any("db", credentials{"carol", "qwerty"}),
+			},
+		}
+	})
+
+	Describe("NewSerializerRedact", func() {
+		It("should create a new object with default configuration", func() {
+			Expect(s.Keys).To(Equal([]string{"*token*", "Password"}))
+			Expect(s.Mode).To(Equal(RedactModeMask))
+			Expect(s.Mask).To(Equal(DefaultRedactMask))
+			Expect(s.HashKey).To(HaveLen(redactHashKeySize))
+		})
+	})
+	Describe("Serialize", func() {
+		It("should mask secrets in properties, fields and message", func() {
+			buf, err := s.Serialize(e)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(buf)).To(Equal(`[INF] "using *** and ***" {refresh_token:"***";` +
+				`count:3;db:"carol:***";AccessToken:"***";login:"bob:***";password:"***";` +
+				`user:alice;}`))
+		})
+		It("should redact nested properties and maps", func() {
+			e.Properties = Properties{
+				"request": Properties{
+					"token": "abcdef",
+					"body":  map[string]interface{}{"password": "hunter2", "id": 7},
+					"note":  "contains secret-77",
+				},
+			}
+			r := s.redact(e)
+			Expect(r.Properties["request"]).To(Equal(Properties{
+				"token": DefaultRedactMask,
+				"body":  map[string]interface{}{"password": DefaultRedactMask, "id": 7},
+				"note":  "contains ***",
+			}))
+		})
+		It("should apply patterns to string properties and fields", func() {
+			e.Properties = Properties{"note": "secret-1 here"}
+			e.Fields = []Field{String("comment", "see secret-2"), Any("list", "secret-3")}
+			r := s.redact(e)
+			Expect(r.Properties).To(Equal(Properties{"note": "*** here"}))
+			Expect(r.Fields).To(Equal([]Field{String("comment", "see ***"), String("list", "***")}))
+		})
+		It("should redact error values", func() {
+			err := fmt.Errorf("login with secret-5 failed: %w", &propertiesError{dryad: "x"})
+			e.Properties = Properties{}
+			e.Fields = nil
+			e.WithError(err)
+			s.Keys = append(s.Keys, "dryad")
+			v := s.redact(e).Properties[ErrorProperty].(*ErrorValue)
+			Expect(v.Message).To(Equal("login with *** failed: dryad x failed"))
+			Expect(v.Causes).To(HaveLen(1))
+			Expect(v.Causes[0].Properties).To(Equal(Properties{"dryad": DefaultRedactMask}))
+
+			e.Properties = Properties{"cause": errors.New("secret-6")}
+			e.Fields = []Field{Err(errors.New("secret-7"))}
+			r := s.redact(e)
+			Expect(r.Properties["cause"].(*ErrorValue).Message).To(Equal(DefaultRedactMask))
+			Expect(r.Fields[0].Value().(*ErrorValue).Message).To(Equal(DefaultRedactMask))
+		})
+		It("should not modify original entry", func() {
+			c := e.clone()
+			_, err := s.Serialize(e)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(e).To(Equal(c))
+		})
+		It("should pass nil entry to wrapped serializer", func() {
+			_, err := s.Serialize(nil)
+			Expect(err).To(Equal(ErrInvalidEntry))
+		})
+	})
+	Describe("AppendSerialize", func() {
+		It("should append entry serialized by wrapped AppendSerializer", func() {
+			expected, err := s.Serialize(e)
+			Expect(err).NotTo(HaveOccurred())
+			buf, err := s.AppendSerialize([]byte("prefix "), e)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(buf).To(Equal(append([]byte("prefix "), expected...)))
+		})
+		Describe("with plain Serializer", func() {
+			var (
+				ctrl *gomock.Controller
+				ms   *MockSerializer
+			)
+			BeforeEach(func() {
+				ctrl = gomock.NewController(GinkgoT())
+				ms = NewMockSerializer(ctrl)
+				s.Serializer = ms
+			})
+			AfterEach(func() {
+				ctrl.Finish()
+			})
+			It("should append redacted entry", func() {
+				ms.EXPECT().Serialize(gomock.Any()).DoAndReturn(func(entry *Entry) ([]byte, error) {
+					Expect(entry.Properties).To(HaveKeyWithValue("password", DefaultRedactMask))
+					return []byte("serialized"), nil
+				})
+				buf, err := s.AppendSerialize([]byte("prefix "), e)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(buf).To(Equal([]byte("prefix serialized")))
+			})
+			It("should return unchanged buffer if serialization fails", func() {
+				testError := errors.New("test error")
+				ms.EXPECT().Serialize(gomock.Any()).Return(nil, testError)
+				buf, err := s.AppendSerialize([]byte("prefix "), e)
+				Expect(err).To(Equal(testError))
+				Expect(buf).To(Equal([]byte("prefix ")))
+			})
+		})
+	})
+	Describe("UsesCallContext", func() {
+		It("should return true if wrapped serializer uses call context", func() {
+			Expect(s.UsesCallContext()).To(BeFalse())
+			s.Serializer = NewSerializerText()
+			Expect(s.UsesCallContext()).To(BeTrue())
+		})
+	})
+	Describe("replace", func() {
+		It("should hash secrets with different keys differently", func() {
+			s.Mode = RedactModeHash
+			hash := s.replace("secret")
+			Expect(s.replace("secret")).To(Equal(hash))
+			other := NewSerializerRedact(nil)
+			other.Mode = RedactModeHash
+			Expect(other.replace("secret")).To(HavePrefix(redactHashPrefix))
+			Expect(other.replace("secret")).NotTo(Equal(hash))
+		})
+	})
+	T.DescribeTable("replace",
+		func(mode RedactMode, length int, expected string) {
+			s.Mode = mode
+			s.TruncateLength = length
+			s.HashKey = []byte("key")
+			Expect(s.replace("zażółć")).To(Equal(expected))
+		},
+		T.Entry("mask", RedactModeMask, 0, DefaultRedactMask),
+		T.Entry("hash", RedactModeHash, 0, "hmac-sha256:04e6e907a041f803"),
+		T.Entry("truncate", RedactModeTruncate, 3, "zaż***"),
+		T.Entry("truncate short", RedactModeTruncate, 10, "zażółć***"),
+		T.Entry("truncate negative", RedactModeTruncate, -1, "***"),
+	)
+})
@@ -17,7 +17,6 @@
 package logger
 
 import (
-	"bytes"
 	"fmt"
 	"io"
 	"sort"
@@ -116,7 +115,9 @@ var levelColoring = map[Level]string{
 // asciiTableSize defines SerializerText.ascii tab size.
 const asciiTableSize = 128
 
-// SerializerText serializes entry to text format.
+// SerializerText serializes entry to text format. Invalid fields are replaced with default values
+// before the first serialization, so fields should not be changed after SerializerText is used.
+// It is safe for concurrent use.
 type SerializerText struct {
 	// TimeFormat defines format for displaying date and time.
 	// Used only when TimestampMode is set to TimestampModeFull.
@@ -141,6 +142,9 @@ type SerializerText struct {
 	// initASCII ensures that initialization of ascii is done only once.
 	initASCII sync.Once
 
+	// initDefaults ensures that invalid fields are fixed only once.
+	initDefaults sync.Once
+
 	// baseTime is the Timestamp from which elapsed time is calculated in TimestampModeDiff.
 	baseTime time.Time
 }
@@ -292,44 +296,69 @@ func (s *SerializerText) appendMessage(buf io.Writer, msg string) (err error) {
 	return err
 }
 
-// appendProperties to log message being created in buf.
-func (s *SerializerText) appendProperties(buf io.Writer, properties Properties) (err error) {
-	if len(properties) == 0 {
+// appendProperties to log message being created in buf. Fields are written first in order
+// in which they were added, followed by properties sorted by key.
+func (s *SerializerText) appendProperties(buf io.Writer, fields []Field,
+	properties Properties) (err error) {
+
+	if len(fields) == 0 && len(properties) == 0 {
 		return
 	}
 	_, err = fmt.Fprintf(buf, "{")
 	if err != nil {
 		return err
 	}
-	keys := make([]string, len(properties))
-	i := 0
+	for _, f := range fields {
+		err = s.appendProperty(buf, f.Key, f.text())
+		if err != nil {
+			return err
+		}
+	}
+	keys := make([]string, 0, len(properties))
 	for k := range properties {
-		keys[i] = k
-		i++
+		if !isShadowed(fields, k) {
+			keys = append(keys, k)
+		}
 	}
 	sort.Strings(keys)
 
 	for _, k := range keys {
-		v := properties[k]
-		format := s.quotingFormat(k)
-		if s.UseColors {
-			format = propkey + format + off
-		}
-		format = format + ":"
-		_, err = fmt.Fprintf(buf, format, k)
+		err = s.appendProperty(buf, k, fmt.Sprint(properties[k]))
 		if err != nil {
 			return err
 		}
+	}
+	_, err = fmt.Fprintf(buf, "}")
+	return err
+}
 
-		value := fmt.Sprint(v)
-		format = s.quotingFormat(value) + ";"
-		_, err = fmt.Fprintf(buf, format, value)
+// appendProperty writes a single key-value pair to buf.
+func (s *SerializerText) appendProperty(buf io.Writer, key, value string) error {
+	format := s.quotingFormat(key)
+	if s.UseColors {
+		format = propkey + format + off
+	}
+	format = format + ":"
+	_, err := fmt.Fprintf(buf, format, key)
+	if err != nil {
+		return err
+	}
+	format = s.quotingFormat(value) + ";"
+	_, err = fmt.Fprintf(buf, format, value)
+	return err
+}
+
+// appendStack to log message being created in buf. Every frame is written in 2 indented lines:
+// function name and source file with line number.
+func (s *SerializerText) appendStack(buf io.Writer, stack []CallContext) (err error) {
+	for _, frame := range stack {
+		_, err = fmt.Fprintf(buf, "\n\t%s\n\t\t%s%s:%d", frame.funcName(), frame.Path,
+			frame.File, frame.Line)
 		if err != nil {
 			return err
 		}
 	}
-	_, err = fmt.Fprintf(buf, "}")
-	return err
+	return nil
 }
 
 // serialize writes parts of Entry to given writer.
@@ -353,19 +382,35 @@ func (s *SerializerText) serialize(entry *Entry, buf io.Writer) error {
 	if err != nil {
 		return err
 	}
-	err = s.appendProperties(buf, entry.Properties)
+	err = s.appendProperties(buf, entry.Fields, entry.Properties)
+	if err != nil {
+		return err
+	}
+	err = s.appendStack(buf, entry.Stack)
 	return err
 }
 
+// UsesCallContext returns true unless CallContextMode is set to CallContextModeNone.
+// It implements CallContextUser interface in SerializerText.
+func (s *SerializerText) UsesCallContext() bool {
+	return s.CallContextMode != CallContextModeNone
+}
+
 // Serialize implements Serializer interface in SerializerText.
 func (s *SerializerText) Serialize(entry *Entry) ([]byte, error) {
-	s.setDefaultsOnInvalid()
+	return serializeCopy(s, entry)
+}
 
-	buf := &bytes.Buffer{}
-	err := s.serialize(entry, buf)
-	if err != nil {
-		return nil, err
-	}
+// AppendSerialize appends entry serialized to text format to dst. Entry is written directly
+// into dst without intermediate buffers.
+// It implements AppendSerializer interface in SerializerText.
+func (s *SerializerText) AppendSerialize(dst []byte, entry *Entry) ([]byte, error) {
+	s.initDefaults.Do(s.setDefaultsOnInvalid)
 
-	return buf.Bytes(), nil
+	w := getAppendWriter(dst)
+	defer putAppendWriter(w)
+	if err := s.serialize(entry, w); err != nil {
+		return dst, err
+	}
+	return w.buf, nil
 }
@@ -21,6 +21,8 @@ import (
 	"errors"
 	"io"
 	"strings"
+	"sync"
+	"testing"
 	"time"
 
 	. "github.com/onsi/ginkgo"
@@ -471,7 +473,7 @@ var _ = Describe("SerializerText", func() {
 		Describe("appendProperties", func() {
 			It("should do nothing when properties are empty", func() {
 				p := Properties{}
-				err := s.appendProperties(buf, p)
+				err := s.appendProperties(buf, nil, p)
 
 				Expect(err).NotTo(HaveOccurred())
 				Expect(buf.Len()).To(BeZero())
@@ -488,7 +490,7 @@ var _ = Describe("SerializerText", func() {
 					},
 					"issues": "",
 				}
-				err := s.appendProperties(buf, p)
+				err := s.appendProperties(buf, nil, p)
 
 				Expect(err).NotTo(HaveOccurred())
 				expected := `{age:37;hash:"#$%%@";issues:"";male:true;name:Alice;skills:` +
@@ -507,7 +509,7 @@ var _ = Describe("SerializerText", func() {
 					},
 					"issues": "",
 				}
-				err := s.appendProperties(buf, p)
+				err := s.appendProperties(buf, nil, p)
 
 				Expect(err).NotTo(HaveOccurred())
 				expected := "{" + propkey + "age" + off + ":37;" + propkey + "hash" + off +
@@ -516,6 +518,26 @@ var _ = Describe("SerializerText", func() {
 					`:"map[coding:7]";}`
 				Expect(buf.String()).To(Equal(expected))
 			})
+			It("should serialize fields in order followed by properties", func() {
+				s.UseColors = false
+				fields := []Field{
+					String("name", "Bob"),
+					Int("age", 42),
+					Duration("took", 1500*time.Millisecond),
+					Bool("male", true),
+					Err(errors.New("some error")),
+				}
+				p := Properties{
+					"name": "Alice",
+					"city": "Warsaw",
+				}
+				err := s.appendProperties(buf, fields, p)
+
+				Expect(err).NotTo(HaveOccurred())
+				expected := `{name:Bob;age:42;took:1.5s;male:true;error:"some error";` +
+					`city:Warsaw;}`
+				Expect(buf.String()).To(Equal(expected))
+			})
 			T.DescribeTable("should return error if writing fails",
 				func(bytes int) {
 					w := NewFailingWriter(bytes, testError)
@@ -523,7 +545,7 @@ var _ = Describe("SerializerText", func() {
 						"name": "Alice",
 					}
 					s.UseColors = false
-					err := s.appendProperties(w, p)
+					err := s.appendProperties(w, nil, p)
 					Expect(err).To(Equal(testError))
 				},
 				//                                          0        1
@@ -537,6 +559,33 @@ var _ = Describe("SerializerText", func() {
 			)
 		})
 	})
+	Describe("appendStack", func() {
+		stack := []CallContext{
+			{Path: "/p/", File: "f.go", Line: 12, Package: "a/b", Function: "g"},
+			{Path: "/p/", File: "h.go", Line: 3, Package: "a/b", Type: "(*t)", Function: "u"},
+		}
+		It("should do nothing when stack is empty", func() {
+			buf := &bytes.Buffer{}
+			err := s.appendStack(buf, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(buf.Len()).To(BeZero())
+		})
+		It("should serialize frames as indented block", func() {
+			buf := &bytes.Buffer{}
+			err := s.appendStack(buf, stack)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(buf.String()).To(Equal("\n\ta/b.g\n\t\t/p/f.go:12\n\ta/b.(*t).u\n\t\t/p/h.go:3"))
+		})
+		T.DescribeTable("should return error if writing fails",
+			func(bytes int) {
+				w := NewFailingWriter(bytes, testError)
+				err := s.appendStack(w, stack)
+				Expect(err).To(Equal(testError))
+			},
+			T.Entry("First frame", 1),
+			T.Entry("Second frame", 20),
+		)
+	})
 	Describe("serialize", func() {
 		T.DescribeTable("should return error if writing fails",
 			func(bytes int) {
@@ -575,6 +624,17 @@ var _ = Describe("SerializerText", func() {
 			T.Entry("Message", 33),
 			T.Entry("Properties", 54),
 		)
+		It("should return error if writing stack fails", func() {
+			s.TimestampMode = TimestampModeNone
+			s.UseColors = false
+			entry := &Entry{
+				Level:   ErrLevel,
+				Message: "message",
+				Stack:   []CallContext{{File: "f.go", Package: "a", Function: "g"}},
+			}
+			err := s.serialize(entry, NewFailingWriter(len("[ERR] message ")+1, testError))
+			Expect(err).To(Equal(testError))
+		})
 		It("should fail if Entry is invalid", func() {
 			w := NewFailingWriter(0, testError)
 			err := s.serialize(nil, w)
@@ -616,4 +676,63 @@ var _ = Describe("SerializerText", func() {
 			Expect(byt).To(BeNil())
 		})
 	})
+	Describe("AppendSerialize", func() {
+		entry := &Entry{
+			Level:   ErrLevel,
+			Message: "message",
+		}
+		BeforeEach(func() {
+			s.UseColors = false
+			s.TimestampMode = TimestampModeNone
+		})
+		It("should append serialized message to given buffer", func() {
+			byt, err := s.AppendSerialize([]byte("prefix "), entry)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(byt)).To(Equal("prefix [ERR] message "))
+		})
+		It("should not share internal buffer between calls", func() {
+			first, err := s.AppendSerialize(nil, entry)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = s.AppendSerialize(nil, &Entry{Level: InfoLevel, Message: "other"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(first)).To(Equal("[ERR] message "))
+		})
+		It("should return unchanged buffer if Entry is invalid", func() {
+			byt, err := s.AppendSerialize([]byte("prefix "), nil)
+			Expect(err).To(Equal(ErrInvalidEntry))
+			Expect(string(byt)).To(Equal("prefix "))
+		})
+		It("should set defaults of zero value once when used concurrently", func() {
+			s = &SerializerText{TimestampMode: TimestampModeFull}
+			var wg sync.WaitGroup
+			for i := 0; i < 4; i++ {
+				wg.Add(1)
+				go func() {
+					defer GinkgoRecover()
+					defer wg.Done()
+					_, err := s.AppendSerialize(nil, entry)
+					Expect(err).NotTo(HaveOccurred())
+				}()
+			}
+			wg.Wait()
+			Expect(s.TimeFormat).To(Equal(DefaultSerializerTextTimeFormat))
+		})
+		It("should allocate less memory than Serialize", func() {
+			buf := make([]byte, 0, defaultBufferSize)
+			appendAllocs := testing.AllocsPerRun(100, func() {
+				_, _ = s.AppendSerialize(buf[:0], entry)
+			})
+			serializeAllocs := testing.AllocsPerRun(100, func() {
+				_, _ = s.Serialize(entry)
+			})
+			Expect(appendAllocs).To(BeNumerically("<", serializeAllocs))
+		})
+	})
+	Describe("UsesCallContext", func() {
+		It("should return false only if call context is not serialized", func() {
+			Expect(s.UsesCallContext()).To(BeTrue())
+			s.CallContextMode = CallContextModeNone
+			Expect(s.UsesCallContext()).To(BeFalse())
+		})
+	})
 })
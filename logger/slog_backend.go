@@ -0,0 +1,144 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+)
+
+// StackProperty defines key of attribute holding stack trace of entries forwarded to slog.Handler.
+const StackProperty = "stack"
+
+// slogLevel converts Level to slog level. It is the inverse of levelFromSlog.
+func slogLevel(level Level) slog.Level {
+	switch level {
+	case EmergLevel:
+		return slog.LevelError + 12
+	case AlertLevel:
+		return slog.LevelError + 8
+	case CritLevel:
+		return slog.LevelError + 4
+	case ErrLevel:
+		return slog.LevelError
+	case WarningLevel:
+		return slog.LevelWarn
+	case NoticeLevel:
+		return slog.LevelInfo + 2
+	case InfoLevel:
+		return slog.LevelInfo
+	}
+	return slog.LevelDebug
+}
+
+// NewSlogBackend creates a Backend forwarding entries to h. Properties and fields are passed
+// as attributes (nested Properties as groups), call context as slog.Source under slog.SourceKey
+// and stack trace under StackProperty.
+func NewSlogBackend(h slog.Handler) Backend {
+	f := &slogForwarder{handler: h}
+	return Backend{
+		Filter:     f,
+		Serializer: f,
+		Writer:     f,
+	}
+}
+
+// slogForwarder passes entries to slog.Handler.
+// It implements Filter, Serializer and Writer interfaces.
+type slogForwarder struct {
+	// handler handles forwarded entries.
+	handler slog.Handler
+}
+
+// Verify accepts entries enabled in handler. It implements Filter interface in slogForwarder.
+func (f *slogForwarder) Verify(entry *Entry) (bool, error) {
+	if entry == nil {
+		return false, ErrInvalidEntry
+	}
+	return f.handler.Enabled(context.Background(), slogLevel(entry.Level)), nil
+}
+
+// Serialize passes entry to handler. Nothing is returned for writing.
+// It implements Serializer interface in slogForwarder.
+func (f *slogForwarder) Serialize(entry *Entry) ([]byte, error) {
+	if entry == nil {
+		return nil, ErrInvalidEntry
+	}
+	return nil, f.handler.Handle(context.Background(), slogRecord(entry))
+}
+
+// Write does nothing as entries are already handled by Serialize.
+// It implements Writer interface in slogForwarder.
+func (*slogForwarder) Write(Level, []byte) (int, error) {
+	return 0, nil
+}
+
+// slogRecord converts entry to slog.Record.
+func slogRecord(entry *Entry) slog.Record {
+	r := slog.NewRecord(entry.Timestamp, slogLevel(entry.Level), entry.Message, 0)
+	if entry.CallContext != nil {
+		r.AddAttrs(slog.Any(slog.SourceKey, &slog.Source{
+			Function: entry.CallContext.funcName(),
+			File:     entry.CallContext.Path + entry.CallContext.File,
+			Line:     entry.CallContext.Line,
+		}))
+	}
+	for _, f := range entry.Fields {
+		r.AddAttrs(slogFieldAttr(f))
+	}
+	r.AddAttrs(slogAttrs(entry.Properties, entry.Fields)...)
+	if len(entry.Stack) > 0 {
+		r.AddAttrs(slog.Any(StackProperty, entry.Stack))
+	}
+	return r
+}
+
+// slogFieldAttr converts field to slog.Attr.
+func slogFieldAttr(f Field) slog.Attr {
+	switch f.Type {
+	case FieldTypeString:
+		return slog.String(f.Key, f.String)
+	case FieldTypeInt:
+		return slog.Int64(f.Key, f.Integer)
+	case FieldTypeError:
+		return slog.Any(f.Key, f.Interface)
+	}
+	return slog.Any(f.Key, f.Value())
+}
+
+// slogAttrs converts properties not shadowed by fields to attributes sorted by key.
+// Nested Properties are converted to groups.
+func slogAttrs(properties Properties, fields []Field) []slog.Attr {
+	keys := make([]string, 0, len(properties))
+	for k := range properties {
+		if !isShadowed(fields, k) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, k := range keys {
+		if group, ok := properties[k].(Properties); ok {
+			value := slog.GroupValue(slogAttrs(group, nil)...)
+			attrs = append(attrs, slog.Attr{Key: k, Value: value})
+			continue
+		}
+		attrs = append(attrs, slog.Any(k, properties[k]))
+	}
+	return attrs
+}
@@ -0,0 +1,120 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	T "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SlogBackend", func() {
+	var (
+		buf *bytes.Buffer
+		b   Backend
+		e   *Entry
+	)
+
+	BeforeEach(func() {
+		buf = new(bytes.Buffer)
+		b = NewSlogBackend(slog.NewJSONHandler(buf, &slog.HandlerOptions{
+			Level: slog.LevelInfo,
+		}))
+		e = &Entry{
+			Level:     WarningLevel,
+			Message:   "message",
+			Timestamp: time.Date(2018, 7, 9, 10, 11, 12, 0, time.UTC),
+			CallContext: &CallContext{
+				Path:     "/src/",
+				File:     "main.go",
+				Line:     7,
+				Package:  "main",
+				Function: "main",
+			},
+			Properties: Properties{
+				"name":  "alice",
+				"age":   37,
+				"skill": Properties{"coding": 7},
+				"env":   "shadowed",
+			},
+			Fields: []Field{
+				String("env", "test"),
+				Int("count", 3),
+				Bool("ok", true),
+				Err(errors.New("test error")),
+			},
+			Stack: []CallContext{{Path: "/src/", File: "main.go", Line: 7, Function: "main"}},
+		}
+	})
+
+	It("should forward entries to slog.Handler", func() {
+		Expect(b.process(e)).To(Succeed())
+		Expect(buf.String()).To(Equal(`{"time":"2018-07-09T10:11:12Z","level":"WARN",` +
+			`"msg":"message","source":{"function":"main.main","file":"/src/main.go","line":7},` +
+			`"env":"test","count":3,"ok":true,"error":"test error","age":37,"name":"alice",` +
+			`"skill":{"coding":7},"stack":[{"path":"/src/","file":"main.go","line":7,` +
+			`"package":"","function":"main"}]}` + "\n"))
+	})
+	It("should pass fully qualified function name of methods", func() {
+		e.CallContext.Package = "github.com/SamsungSLAV/slav/logger"
+		e.CallContext.Type = "(*Logger)"
+		e.CallContext.Function = "Info"
+		Expect(b.process(e)).To(Succeed())
+		Expect(buf.String()).To(ContainSubstring(
+			`"function":"github.com/SamsungSLAV/slav/logger.(*Logger).Info"`))
+	})
+	It("should filter entries not enabled in slog.Handler", func() {
+		e.Level = DebugLevel
+		Expect(b.process(e)).To(Succeed())
+		Expect(buf.Len()).To(BeZero())
+	})
+	It("should forward entries logged by Logger", func() {
+		L := NewLogger()
+		L.AddBackend("slog", b)
+		L.Info("message")
+		Expect(buf.String()).To(ContainSubstring(`"level":"INFO","msg":"message","source":{`))
+		Expect(L.Stats()["slog"].Counters.Accepted).To(Equal(uint64(1)))
+	})
+	It("should return error for nil entry", func() {
+		pass, err := b.Filter.Verify(nil)
+		Expect(err).To(Equal(ErrInvalidEntry))
+		Expect(pass).To(BeFalse())
+		_, err = b.Serializer.Serialize(nil)
+		Expect(err).To(Equal(ErrInvalidEntry))
+	})
+	T.DescribeTable("should map levels to slog levels and back",
+		func(level Level) {
+			Expect(levelFromSlog(slogLevel(level))).To(Equal(level))
+		},
+		T.Entry("emergency", EmergLevel),
+		T.Entry("alert", AlertLevel),
+		T.Entry("critical", CritLevel),
+		T.Entry("error", ErrLevel),
+		T.Entry("warning", WarningLevel),
+		T.Entry("notice", NoticeLevel),
+		T.Entry("info", InfoLevel),
+		T.Entry("debug", DebugLevel),
+	)
+	It("should map invalid level to debug level", func() {
+		Expect(slogLevel(DebugLevel + 1)).To(Equal(slog.LevelDebug))
+	})
+})
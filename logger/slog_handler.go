@@ -0,0 +1,161 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+// levelFromSlog converts slog level to Level. Levels between slog's levels are mapped to more
+// important Levels, e.g. slog.LevelInfo+2 becomes NoticeLevel and slog.LevelError+4 CritLevel.
+func levelFromSlog(l slog.Level) Level {
+	switch {
+	case l < slog.LevelInfo:
+		return DebugLevel
+	case l == slog.LevelInfo:
+		return InfoLevel
+	case l < slog.LevelWarn:
+		return NoticeLevel
+	case l < slog.LevelError:
+		return WarningLevel
+	case l < slog.LevelError+4:
+		return ErrLevel
+	case l < slog.LevelError+8:
+		return CritLevel
+	case l < slog.LevelError+12:
+		return AlertLevel
+	}
+	return EmergLevel
+}
+
+// SlogHandler is a slog.Handler logging records with Logger. Attributes are logged as properties
+// (groups as nested Properties) and source of records as call context.
+type SlogHandler struct {
+	// logger logs records.
+	logger *Logger
+	// properties contain attributes added with WithAttrs.
+	properties Properties
+	// groups contain names of groups opened with WithGroup.
+	groups []string
+}
+
+// NewSlogHandler creates and returns a new SlogHandler logging records with l.
+func NewSlogHandler(l *Logger) *SlogHandler {
+	return &SlogHandler{logger: l}
+}
+
+// Enabled verifies if records with given level pass Logger's threshold.
+// It implements slog.Handler interface in SlogHandler.
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.logger.PassThreshold(levelFromSlog(level))
+}
+
+// Handle logs record with properties carried by ctx (see ContextWithProperties). It implements
+// slog.Handler interface in SlogHandler. Call context is the source of the record. If record has
+// no program counter, it is the caller of Handle (skipping frames of log/slog package).
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	pc := r.PC
+	if pc == 0 {
+		// Skip Handle. Frames of log/slog are skipped by findCallerPC.
+		pc = findCallerPC(1, maxCallContextDepth)
+	}
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	e := h.logger.WithContext(ctx).WithProperties(addSlogAttrs(h.properties, h.groups, attrs))
+	e.WithCaller(pc).process(levelFromSlog(r.Level), r.Message)
+	return nil
+}
+
+// WithAttrs returns a new SlogHandler logging given attributes with every record.
+// It implements slog.Handler interface in SlogHandler.
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	c := *h
+	c.properties = addSlogAttrs(h.properties, h.groups, attrs)
+	return &c
+}
+
+// WithGroup returns a new SlogHandler logging all further attributes in given group.
+// It implements slog.Handler interface in SlogHandler.
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	c := *h
+	c.groups = append(h.groups[:len(h.groups):len(h.groups)], name)
+	return &c
+}
+
+// addSlogAttrs returns a copy of properties with attributes added in group defined by path
+// of group names. Properties are never modified, so they can be shared by handlers.
+func addSlogAttrs(properties Properties, groups []string, attrs []slog.Attr) Properties {
+	c := make(Properties, len(properties)+len(attrs))
+	for k, v := range properties {
+		c[k] = v
+	}
+	if len(groups) > 0 {
+		group, _ := c[groups[0]].(Properties)
+		if group = addSlogAttrs(group, groups[1:], attrs); len(group) > 0 {
+			c[groups[0]] = group
+		}
+		return c
+	}
+	for _, a := range attrs {
+		addSlogAttr(c, a)
+	}
+	return c
+}
+
+// addSlogAttr adds attribute to properties following rules of slog.Handler: empty attributes
+// and groups are omitted and attributes of groups with empty key are inlined.
+func addSlogAttr(properties Properties, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() != slog.KindGroup {
+		properties[a.Key] = slogValue(a.Value)
+		return
+	}
+	attrs := a.Value.Group()
+	if len(attrs) == 0 {
+		return
+	}
+	if a.Key == "" {
+		for _, ga := range attrs {
+			addSlogAttr(properties, ga)
+		}
+		return
+	}
+	group, _ := properties[a.Key].(Properties)
+	properties[a.Key] = addSlogAttrs(group, nil, attrs)
+}
+
+// slogValue returns value of resolved slog.Value. Errors are converted to ErrorValue.
+func slogValue(v slog.Value) interface{} {
+	value := v.Any()
+	if err, ok := value.(error); ok {
+		return NewErrorValue(err)
+	}
+	return value
+}
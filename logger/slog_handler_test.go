@@ -0,0 +1,143 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"runtime"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	T "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SlogHandler", func() {
+	const thisFile = "slog_handler_test.go"
+	var (
+		L *Logger
+		f *filterCollector
+		s *slog.Logger
+	)
+
+	BeforeEach(func() {
+		L = NewLogger()
+		f = new(filterCollector)
+		L.AddBackend("collector", Backend{
+			Filter:     f,
+			Serializer: NewSerializerJSON(),
+			Writer:     new(writerCollector),
+		})
+		s = slog.New(NewSlogHandler(L))
+	})
+
+	lastEntry := func() *Entry {
+		entries := f.Entries()
+		Expect(entries).NotTo(BeEmpty())
+		return entries[len(entries)-1]
+	}
+
+	It("should log records with source as call context", func() {
+		_, _, line, _ := runtime.Caller(0)
+		s.Warn("message", "count", 7)
+		e := lastEntry()
+		Expect(e.Level).To(Equal(WarningLevel))
+		Expect(e.Message).To(Equal("message"))
+		Expect(e.Properties).To(Equal(Properties{"count": int64(7)}))
+		Expect(e.CallContext.File).To(Equal(thisFile))
+		Expect(e.CallContext.Line).To(Equal(line + 1))
+	})
+	It("should capture call context if record has no source", func() {
+		h := NewSlogHandler(L)
+		r := slog.NewRecord(time.Now(), slog.LevelError, "message", 0)
+		_, _, line, _ := runtime.Caller(0)
+		Expect(h.Handle(context.Background(), r)).To(Succeed())
+		e := lastEntry()
+		Expect(e.CallContext.File).To(Equal(thisFile))
+		Expect(e.CallContext.Line).To(Equal(line + 1))
+		Expect(markedAsHelper((*SlogHandler).Handle)).To(BeFalse())
+	})
+	It("should log properties carried by context", func() {
+		ctx := ContextWithProperties(context.Background(), Properties{"job": 1, "count": 0})
+		s.InfoContext(ctx, "message", "count", 7)
+		Expect(lastEntry().Properties).To(Equal(Properties{"job": 1, "count": int64(7)}))
+	})
+	It("should respect Logger's threshold", func() {
+		Expect(s.Enabled(context.Background(), slog.LevelInfo)).To(BeTrue())
+		Expect(s.Enabled(context.Background(), slog.LevelDebug)).To(BeFalse())
+		s.Debug("message")
+		Expect(f.Entries()).To(BeEmpty())
+	})
+	It("should log attributes and groups as properties", func() {
+		testError := errors.New("test error")
+		s.With("service", "boruta").WithGroup("req").With("id", 3).WithGroup("").Info(
+			"message",
+			slog.Group("user", "name", "alice", slog.Group("empty")),
+			slog.Group("", "inlined", true),
+			slog.Attr{},
+			"err", testError,
+		)
+		Expect(lastEntry().Properties).To(Equal(Properties{
+			"service": "boruta",
+			"req": Properties{
+				"id":      int64(3),
+				"user":    Properties{"name": "alice"},
+				"inlined": true,
+				"err":     NewErrorValue(testError),
+			},
+		}))
+	})
+	It("should omit groups without attributes", func() {
+		s.WithGroup("req").Info("message")
+		Expect(lastEntry().Properties).To(BeEmpty())
+	})
+	It("should not share attributes between handlers", func() {
+		base := s.WithGroup("req").With("id", 1)
+		base.With("a", 1).Info("first")
+		base.With("b", 2).Info("second")
+		Expect(lastEntry().Properties).To(Equal(Properties{
+			"req": Properties{"id": int64(1), "b": int64(2)},
+		}))
+	})
+	It("should resolve LogValuer attributes", func() {
+		s.Info("message", "lazy", slogValuer("value"))
+		Expect(lastEntry().Properties).To(Equal(Properties{"lazy": "value"}))
+	})
+	T.DescribeTable("should map slog levels",
+		func(level slog.Level, expected Level) {
+			Expect(levelFromSlog(level)).To(Equal(expected))
+		},
+		T.Entry("below debug", slog.LevelDebug-4, DebugLevel),
+		T.Entry("debug", slog.LevelDebug, DebugLevel),
+		T.Entry("info", slog.LevelInfo, InfoLevel),
+		T.Entry("notice", slog.LevelInfo+2, NoticeLevel),
+		T.Entry("warn", slog.LevelWarn, WarningLevel),
+		T.Entry("error", slog.LevelError, ErrLevel),
+		T.Entry("critical", slog.LevelError+4, CritLevel),
+		T.Entry("alert", slog.LevelError+8, AlertLevel),
+		T.Entry("emergency", slog.LevelError+12, EmergLevel),
+	)
+})
+
+// slogValuer is a slog.LogValuer returning itself as a string.
+type slogValuer string
+
+func (v slogValuer) LogValue() slog.Value {
+	return slog.StringValue(string(v))
+}
@@ -0,0 +1,178 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// latencyBuckets defines upper bounds of backends' latency histogram buckets.
+// The last bucket of histogram has no upper bound and counts all slower entries.
+var latencyBuckets = []time.Duration{
+	10 * time.Microsecond,
+	100 * time.Microsecond,
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	time.Second,
+}
+
+// Counters contains statistics of entries processed by a backend.
+type Counters struct {
+	// Accepted is the number of entries that passed backend's filter and were serialized
+	// and written successfully. Entries that passed filter but failed later are counted
+	// in Errors.
+	Accepted uint64 `json:"accepted"`
+	// Filtered is the number of entries rejected by backend's filter.
+	Filtered uint64 `json:"filtered"`
+	// Skipped is the number of entries not passed to backend disabled by error handler.
+	Skipped uint64 `json:"skipped"`
+	// Bytes is the number of serialized bytes passed to backend's writer.
+	Bytes uint64 `json:"bytes"`
+	// Errors is the number of failures of backend.
+	Errors uint64 `json:"errors"`
+}
+
+// add increases counters by values of other counters.
+func (c *Counters) add(o Counters) {
+	c.Accepted += o.Accepted
+	c.Filtered += o.Filtered
+	c.Skipped += o.Skipped
+	c.Bytes += o.Bytes
+	c.Errors += o.Errors
+}
+
+// LatencyBucket is a single bucket of latency histogram.
+type LatencyBucket struct {
+	// UpperBound is the maximum latency counted in the bucket. Zero means no bound.
+	UpperBound time.Duration `json:"upper_bound"`
+	// Count is the number of entries processed with latency within the bucket.
+	Count uint64 `json:"count"`
+}
+
+// BackendStats contains statistics of a single backend.
+type BackendStats struct {
+	// Counters contains statistics of all entries.
+	Counters
+	// Levels contains statistics of entries with every level. Level names are used as keys.
+	Levels map[string]Counters `json:"levels"`
+	// Latency is a histogram of time used by backend to process accepted entries.
+	Latency []LatencyBucket `json:"latency"`
+}
+
+// levelCounters contains counters of a single level updated atomically.
+type levelCounters struct {
+	accepted, filtered, skipped, bytes, errors uint64
+}
+
+// load returns current values of counters.
+func (c *levelCounters) load() Counters {
+	return Counters{
+		Accepted: atomic.LoadUint64(&c.accepted),
+		Filtered: atomic.LoadUint64(&c.filtered),
+		Skipped:  atomic.LoadUint64(&c.skipped),
+		Bytes:    atomic.LoadUint64(&c.bytes),
+		Errors:   atomic.LoadUint64(&c.errors),
+	}
+}
+
+// backendStats collects statistics of a backend. All its methods can be called on nil pointer.
+type backendStats struct {
+	// levels contains counters of all valid levels and an additional one for invalid levels.
+	levels [DebugLevel + 2]levelCounters
+	// latency contains histogram buckets' counters.
+	latency []uint64
+}
+
+// newBackendStats creates a new backendStats object.
+func newBackendStats() *backendStats {
+	return &backendStats{
+		latency: make([]uint64, len(latencyBuckets)+1),
+	}
+}
+
+// level returns counters of given level.
+func (s *backendStats) level(l Level) *levelCounters {
+	if !l.IsValid() {
+		l = DebugLevel + 1
+	}
+	return &s.levels[l]
+}
+
+// accepted counts an entry that passed filter and was successfully written in duration d
+// with n bytes passed to writer.
+func (s *backendStats) accepted(l Level, n int, d time.Duration) {
+	if s == nil {
+		return
+	}
+	c := s.level(l)
+	atomic.AddUint64(&c.accepted, 1)
+	atomic.AddUint64(&c.bytes, uint64(n))
+	i := 0
+	for i < len(latencyBuckets) && d > latencyBuckets[i] {
+		i++
+	}
+	atomic.AddUint64(&s.latency[i], 1)
+}
+
+// filtered counts an entry rejected by filter.
+func (s *backendStats) filtered(l Level) {
+	if s == nil {
+		return
+	}
+	atomic.AddUint64(&s.level(l).filtered, 1)
+}
+
+// skipped counts an entry not passed to disabled backend.
+func (s *backendStats) skipped(l Level) {
+	if s == nil {
+		return
+	}
+	atomic.AddUint64(&s.level(l).skipped, 1)
+}
+
+// failed counts a failure of backend.
+func (s *backendStats) failed(l Level) {
+	if s == nil {
+		return
+	}
+	atomic.AddUint64(&s.level(l).errors, 1)
+}
+
+// snapshot returns current statistics.
+func (s *backendStats) snapshot() BackendStats {
+	ret := BackendStats{
+		Levels:  make(map[string]Counters),
+		Latency: make([]LatencyBucket, len(s.latency)),
+	}
+	for i := range s.levels {
+		c := s.levels[i].load()
+		if c == (Counters{}) {
+			continue
+		}
+		ret.Levels[Level(i).String()] = c
+		ret.Counters.add(c)
+	}
+	for i := range s.latency {
+		ret.Latency[i].Count = atomic.LoadUint64(&s.latency[i])
+		if i < len(latencyBuckets) {
+			ret.Latency[i].UpperBound = latencyBuckets[i]
+		}
+	}
+	return ret
+}
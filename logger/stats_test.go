@@ -0,0 +1,91 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Stats", func() {
+	var s *backendStats
+
+	BeforeEach(func() {
+		s = newBackendStats()
+	})
+
+	Describe("backendStats", func() {
+		It("should return empty statistics", func() {
+			ret := s.snapshot()
+			Expect(ret.Counters).To(BeZero())
+			Expect(ret.Levels).To(BeEmpty())
+			Expect(ret.Latency).To(HaveLen(len(latencyBuckets) + 1))
+			for i, b := range ret.Latency[:len(latencyBuckets)] {
+				Expect(b.UpperBound).To(Equal(latencyBuckets[i]))
+				Expect(b.Count).To(BeZero())
+			}
+			Expect(ret.Latency[len(latencyBuckets)].UpperBound).To(BeZero())
+		})
+		It("should count entries per level", func() {
+			s.accepted(ErrLevel, 10, 0)
+			s.accepted(ErrLevel, 5, 0)
+			s.filtered(DebugLevel)
+			s.skipped(ErrLevel)
+			s.failed(WarningLevel)
+			s.failed(Level(0xBADC0DE))
+
+			ret := s.snapshot()
+			Expect(ret.Counters).To(Equal(Counters{
+				Accepted: 2,
+				Filtered: 1,
+				Skipped:  1,
+				Bytes:    15,
+				Errors:   2,
+			}))
+			Expect(ret.Levels).To(Equal(map[string]Counters{
+				ErrLevelStr:     {Accepted: 2, Skipped: 1, Bytes: 15},
+				DebugLevelStr:   {Filtered: 1},
+				WarningLevelStr: {Errors: 1},
+				UnknownLevelStr: {Errors: 1},
+			}))
+		})
+		It("should build latency histogram", func() {
+			s.accepted(InfoLevel, 0, 5*time.Microsecond)
+			s.accepted(InfoLevel, 0, 10*time.Microsecond)
+			s.accepted(InfoLevel, 0, 2*time.Millisecond)
+			s.accepted(InfoLevel, 0, time.Minute)
+
+			ret := s.snapshot()
+			counts := make([]uint64, len(ret.Latency))
+			for i, b := range ret.Latency {
+				counts[i] = b.Count
+			}
+			Expect(counts).To(Equal([]uint64{2, 0, 0, 1, 0, 0, 1}))
+		})
+		It("should ignore calls on nil object", func() {
+			s = nil
+			Expect(func() {
+				s.accepted(InfoLevel, 1, 0)
+				s.filtered(InfoLevel)
+				s.skipped(InfoLevel)
+				s.failed(InfoLevel)
+			}).NotTo(Panic())
+		})
+	})
+})
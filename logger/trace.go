@@ -0,0 +1,191 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	// TraceIDProperty defines key of field holding ID of the distributed trace.
+	TraceIDProperty = "trace_id"
+	// SpanIDProperty defines key of field holding ID of the span within distributed trace.
+	SpanIDProperty = "span_id"
+)
+
+const (
+	// TraceparentHeader is the HTTP header carrying W3C traceparent.
+	TraceparentHeader = "traceparent"
+	// TracestateHeader is the HTTP header carrying W3C tracestate.
+	TracestateHeader = "tracestate"
+)
+
+// TraceFlagSampled is the trace flag set if the caller may have recorded the trace.
+const TraceFlagSampled byte = 0x01
+
+const (
+	// traceparentVersion is the supported version of traceparent format.
+	traceparentVersion = "00"
+	// traceparentLength is the length of traceparent of supported version.
+	traceparentLength = len("00-") + 2*traceIDSize + len("-") + 2*spanIDSize + len("-00")
+	// traceIDSize is the number of bytes of trace ID.
+	traceIDSize = 16
+	// spanIDSize is the number of bytes of span ID.
+	spanIDSize = 8
+)
+
+// TraceContext identifies an operation (span) within distributed trace as defined by W3C
+// Trace Context (https://www.w3.org/TR/trace-context/). IDs are lowercase hexadecimal strings.
+// Entries of processes taking part in the same trace have the same TraceIDProperty, so they
+// can be joined.
+type TraceContext struct {
+	// TraceID identifies the whole trace.
+	TraceID string
+	// SpanID identifies the operation within the trace.
+	SpanID string
+	// Flags contains trace flags, e.g. TraceFlagSampled.
+	Flags byte
+	// State contains vendor specific tracestate, which is propagated unchanged.
+	State string
+}
+
+// NewTraceContext creates and returns TraceContext of a new sampled trace with random IDs.
+func NewTraceContext() TraceContext {
+	return TraceContext{
+		TraceID: randomID(traceIDSize),
+		SpanID:  randomID(spanIDSize),
+		Flags:   TraceFlagSampled,
+	}
+}
+
+// NewSpan returns TraceContext of a new span with random ID within the same trace.
+func (tc TraceContext) NewSpan() TraceContext {
+	tc.SpanID = randomID(spanIDSize)
+	return tc
+}
+
+// Traceparent returns W3C traceparent describing tc.
+func (tc TraceContext) Traceparent() string {
+	return fmt.Sprintf("%s-%s-%s-%02x", traceparentVersion, tc.TraceID, tc.SpanID, tc.Flags)
+}
+
+// ParseTraceparent parses W3C traceparent and tracestate into TraceContext. Versions higher
+// than supported are parsed as described by the specification. ErrInvalidTraceparent
+// is returned if traceparent is malformed.
+func ParseTraceparent(traceparent, tracestate string) (TraceContext, error) {
+	if !isValidTraceparentVersion(traceparent) {
+		return TraceContext{}, ErrInvalidTraceparent
+	}
+	parts := strings.SplitN(traceparent[3:traceparentLength], "-", 3)
+	if len(parts) != 3 || !isHexID(parts[0], traceIDSize) || !isHexID(parts[1], spanIDSize) ||
+		!isHex(parts[2]) {
+		return TraceContext{}, ErrInvalidTraceparent
+	}
+	flags, _ := hex.DecodeString(parts[2])
+	return TraceContext{
+		TraceID: parts[0],
+		SpanID:  parts[1],
+		Flags:   flags[0],
+		State:   strings.TrimSpace(tracestate),
+	}, nil
+}
+
+// isValidTraceparentVersion verifies version of traceparent and length required by it.
+func isValidTraceparentVersion(traceparent string) bool {
+	if len(traceparent) < traceparentLength || traceparent[2] != '-' {
+		return false
+	}
+	version := traceparent[:2]
+	if version == traceparentVersion {
+		return len(traceparent) == traceparentLength
+	}
+	// Higher versions may append more fields separated with '-'.
+	return isHex(version) && version != "ff" &&
+		(len(traceparent) == traceparentLength || traceparent[traceparentLength] == '-')
+}
+
+// TraceFromHTTPHeader returns TraceContext described by traceparent and tracestate headers.
+func TraceFromHTTPHeader(h http.Header) (TraceContext, error) {
+	return ParseTraceparent(h.Get(TraceparentHeader), strings.Join(h.Values(TracestateHeader), ","))
+}
+
+// SetTraceHTTPHeader sets traceparent and tracestate headers describing tc.
+func SetTraceHTTPHeader(h http.Header, tc TraceContext) {
+	h.Set(TraceparentHeader, tc.Traceparent())
+	h.Del(TracestateHeader)
+	if tc.State != "" {
+		h.Set(TracestateHeader, tc.State)
+	}
+}
+
+// ContextWithTrace returns a copy of ctx carrying tc. IDs of the trace and the span are added
+// to properties carried by ctx, so entries created with WithContext have them.
+func ContextWithTrace(ctx context.Context, tc TraceContext) context.Context {
+	ctx = ContextWithProperties(ctx, Properties{
+		TraceIDProperty: tc.TraceID,
+		SpanIDProperty:  tc.SpanID,
+	})
+	return context.WithValue(ctx, traceContextKey, tc)
+}
+
+// TraceFromContext returns TraceContext carried by ctx. If ctx carries none, false is returned.
+func TraceFromContext(ctx context.Context) (TraceContext, bool) {
+	tc, ok := ctx.Value(traceContextKey).(TraceContext)
+	return tc, ok
+}
+
+// WithTrace adds IDs of the trace and the span of tc to the log message.
+func (e *Entry) WithTrace(tc TraceContext) *Entry {
+	return e.WithFields(String(TraceIDProperty, tc.TraceID), String(SpanIDProperty, tc.SpanID))
+}
+
+// randomID returns lowercase hexadecimal representation of size random bytes, which are
+// not all zero.
+func randomID(size int) string {
+	id := make([]byte, size)
+	for {
+		// Reading random bytes does not fail on supported platforms.
+		_, _ = rand.Read(id)
+		for _, b := range id {
+			if b != 0 {
+				return hex.EncodeToString(id)
+			}
+		}
+	}
+}
+
+// isHexID returns true if s is lowercase hexadecimal representation of size bytes, which are
+// not all zero.
+func isHexID(s string, size int) bool {
+	return len(s) == 2*size && isHex(s) && strings.Trim(s, "0") != ""
+}
+
+// isHex returns true if s contains only lowercase hexadecimal digits.
+func isHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,151 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package logger
+
+import (
+	"context"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	T "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TraceContext", func() {
+	const (
+		traceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+		spanID      = "00f067aa0ba902b7"
+		traceparent = "00-" + traceID + "-" + spanID + "-01"
+	)
+
+	Describe("NewTraceContext", func() {
+		It("should create sampled trace with random IDs", func() {
+			tc := NewTraceContext()
+			Expect(tc.TraceID).To(MatchRegexp("^[0-9a-f]{32}$"))
+			Expect(tc.SpanID).To(MatchRegexp("^[0-9a-f]{16}$"))
+			Expect(tc.Flags).To(Equal(TraceFlagSampled))
+			Expect(tc.State).To(BeEmpty())
+			Expect(NewTraceContext().TraceID).NotTo(Equal(tc.TraceID))
+		})
+	})
+	Describe("NewSpan", func() {
+		It("should create span within the same trace", func() {
+			tc := TraceContext{TraceID: traceID, SpanID: spanID, Flags: 1, State: "a=1"}
+			span := tc.NewSpan()
+			Expect(span.TraceID).To(Equal(traceID))
+			Expect(span.SpanID).To(MatchRegexp("^[0-9a-f]{16}$"))
+			Expect(span.SpanID).NotTo(Equal(spanID))
+			Expect(span.Flags).To(Equal(tc.Flags))
+			Expect(span.State).To(Equal(tc.State))
+		})
+	})
+	Describe("ParseTraceparent", func() {
+		It("should parse traceparent and tracestate", func() {
+			tc, err := ParseTraceparent(traceparent, " a=1,b=2 ")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tc).To(Equal(TraceContext{
+				TraceID: traceID,
+				SpanID:  spanID,
+				Flags:   TraceFlagSampled,
+				State:   "a=1,b=2",
+			}))
+			Expect(tc.Traceparent()).To(Equal(traceparent))
+		})
+		T.DescribeTable("should accept traceparent of higher version",
+			func(traceparent string) {
+				tc, err := ParseTraceparent(traceparent, "")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(tc.TraceID).To(Equal(traceID))
+				Expect(tc.SpanID).To(Equal(spanID))
+				Expect(tc.Flags).To(Equal(byte(0x09)))
+			},
+			T.Entry("same format", "01-"+traceID+"-"+spanID+"-09"),
+			T.Entry("more fields", "cc-"+traceID+"-"+spanID+"-09-future"),
+		)
+		T.DescribeTable("should reject malformed traceparent",
+			func(traceparent string) {
+				_, err := ParseTraceparent(traceparent, "")
+				Expect(err).To(Equal(ErrInvalidTraceparent))
+			},
+			T.Entry("empty", ""),
+			T.Entry("too short", "00-"+traceID+"-"+spanID+"-1"),
+			T.Entry("too long", traceparent+"-future"),
+			T.Entry("forbidden version", "ff-"+traceID+"-"+spanID+"-01"),
+			T.Entry("uppercase", "00-"+"4BF92F3577B34DA6A3CE929D0E0E4736-"+spanID+"-01"),
+			T.Entry("zero trace ID", "00-00000000000000000000000000000000-"+spanID+"-01"),
+			T.Entry("zero span ID", "00-"+traceID+"-0000000000000000-01"),
+			T.Entry("wrong separator", "00_"+traceID+"-"+spanID+"-01"),
+			T.Entry("misplaced separator", "00-"+traceID+spanID[:1]+"-"+spanID[1:]+"-01"),
+			T.Entry("invalid flags", "00-"+traceID+"-"+spanID+"-0x"),
+			T.Entry("invalid future field", "01-"+traceID+"-"+spanID+"-01future"),
+		)
+	})
+	Describe("HTTP headers", func() {
+		It("should set and get trace context", func() {
+			h := make(http.Header)
+			h.Set(TracestateHeader, "old=1")
+			tc := TraceContext{TraceID: traceID, SpanID: spanID, Flags: 1, State: "a=1"}
+			SetTraceHTTPHeader(h, tc)
+			Expect(h.Get(TraceparentHeader)).To(Equal(traceparent))
+			Expect(h.Values(TracestateHeader)).To(Equal([]string{"a=1"}))
+			Expect(TraceFromHTTPHeader(h)).To(Equal(tc))
+
+			tc.State = ""
+			SetTraceHTTPHeader(h, tc)
+			Expect(h.Values(TracestateHeader)).To(BeEmpty())
+		})
+		It("should join multiple tracestate headers", func() {
+			h := make(http.Header)
+			h.Set(TraceparentHeader, traceparent)
+			h.Add(TracestateHeader, "a=1")
+			h.Add(TracestateHeader, "b=2")
+			tc, err := TraceFromHTTPHeader(h)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tc.State).To(Equal("a=1,b=2"))
+		})
+		It("should fail if there is no traceparent header", func() {
+			_, err := TraceFromHTTPHeader(make(http.Header))
+			Expect(err).To(Equal(ErrInvalidTraceparent))
+		})
+	})
+	Describe("Context", func() {
+		It("should carry trace context and its properties", func() {
+			tc := TraceContext{TraceID: traceID, SpanID: spanID}
+			ctx := ContextWithTrace(context.Background(), tc)
+			actual, ok := TraceFromContext(ctx)
+			Expect(ok).To(BeTrue())
+			Expect(actual).To(Equal(tc))
+			Expect(ContextProperties(ctx)).To(Equal(Properties{
+				TraceIDProperty: traceID,
+				SpanIDProperty:  spanID,
+			}))
+		})
+		It("should return false if context carries no trace", func() {
+			_, ok := TraceFromContext(context.Background())
+			Expect(ok).To(BeFalse())
+		})
+	})
+	Describe("WithTrace", func() {
+		It("should add IDs as fields of entry", func() {
+			e := NewLogger().newEntry().WithTrace(TraceContext{TraceID: traceID, SpanID: spanID})
+			Expect(e.Fields).To(Equal([]Field{
+				String(TraceIDProperty, traceID),
+				String(SpanIDProperty, spanID),
+			}))
+		})
+	})
+})
@@ -19,6 +19,7 @@ package logger
 import (
 	"io/ioutil"
 	"os"
+	"sync"
 )
 
 const thisPackage = string("github.com/SamsungSLAV/slav/logger")
@@ -40,3 +41,88 @@ func withStderrMocked(testFunction func()) string {
 	buffer, _ := ioutil.ReadAll(r)
 	return string(buffer)
 }
+
+// writerCollector is a Writer collecting all written messages.
+type writerCollector struct {
+	mutex    sync.Mutex
+	levels   []Level
+	messages []string
+}
+
+func (w *writerCollector) Write(level Level, p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.levels = append(w.levels, level)
+	w.messages = append(w.messages, string(p))
+	return len(p), nil
+}
+
+func (w *writerCollector) Messages() []string {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return append([]string(nil), w.messages...)
+}
+
+func (w *writerCollector) Levels() []Level {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return append([]Level(nil), w.levels...)
+}
+
+// newPlainSerializerText creates SerializerText producing output that is easy to compare:
+// only level, message and properties.
+func newPlainSerializerText() *SerializerText {
+	s := NewSerializerText()
+	s.TimestampMode = TimestampModeNone
+	s.CallContextMode = CallContextModeNone
+	s.UseColors = false
+	return s
+}
+
+// stringerFunc is a fmt.Stringer calling itself to build the string.
+type stringerFunc func() string
+
+func (f stringerFunc) String() string {
+	return f()
+}
+
+// filterCollector is a Filter collecting copies of all entries and rejecting them.
+type filterCollector struct {
+	mutex   sync.Mutex
+	entries []*Entry
+}
+
+func (f *filterCollector) Verify(entry *Entry) (bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.entries = append(f.entries, entry.clone())
+	return false, nil
+}
+
+func (f *filterCollector) Entries() []*Entry {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]*Entry(nil), f.entries...)
+}
+
+// flushCounter is a Writer counting calls of Flush method and returning given error from it.
+type flushCounter struct {
+	flushed int
+	err     error
+}
+
+func (*flushCounter) Write(_ Level, p []byte) (int, error) {
+	return len(p), nil
+}
+
+func (f *flushCounter) Flush() error {
+	f.flushed++
+	return f.err
+}
+
+// filterFunc is a Filter calling itself to verify entries.
+type filterFunc func(*Entry) (bool, error)
+
+func (f filterFunc) Verify(entry *Entry) (bool, error) {
+	return f(entry)
+}
@@ -0,0 +1,127 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+// Package zapadapter provides zap core writing entries of zap loggers into SLAV logger.
+package zapadapter
+
+import (
+	"github.com/SamsungSLAV/slav/logger"
+	"github.com/SamsungSLAV/slav/logger/internal/caller"
+	"go.uber.org/zap/zapcore"
+)
+
+// zapPackages are prefixes of functions of zap and its subpackages. Their frames are skipped
+// while looking for the caller, if zap does not report it.
+var zapPackages = []string{"go.uber.org/zap.", "go.uber.org/zap/"}
+
+// NameProperty is the property containing name of zap logger.
+const NameProperty = "logger"
+
+// Level converts zap level to logger.Level. Levels of entries, which panic or terminate
+// the program, are the most important ones.
+func Level(level zapcore.Level) logger.Level {
+	switch level {
+	case zapcore.FatalLevel:
+		return logger.EmergLevel
+	case zapcore.PanicLevel:
+		return logger.AlertLevel
+	case zapcore.DPanicLevel:
+		return logger.CritLevel
+	case zapcore.ErrorLevel:
+		return logger.ErrLevel
+	case zapcore.WarnLevel:
+		return logger.WarningLevel
+	case zapcore.InfoLevel:
+		return logger.InfoLevel
+	}
+	return logger.DebugLevel
+}
+
+// Core is a zapcore.Core logging entries with logger.Logger. Fields of entries are logged
+// as properties, with errors converted to logger.ErrorValue, and caller reported by zap
+// (or the first function outside of zap) as call context.
+type Core struct {
+	logger     *logger.Logger
+	properties logger.Properties
+}
+
+// NewCore creates and returns a new Core logging entries with l. It can be used to create
+// zap logger with zap.New(zapadapter.NewCore(l)) or combined with other cores
+// with zapcore.NewTee.
+func NewCore(l *logger.Logger) *Core {
+	return &Core{logger: l}
+}
+
+// Enabled checks if entries with given level pass threshold of the logger. It implements
+// zapcore.LevelEnabler interface in Core.
+func (c *Core) Enabled(level zapcore.Level) bool {
+	return c.logger.PassThreshold(Level(level))
+}
+
+// With returns a new Core adding fields to all logged entries. It implements zapcore.Core
+// interface in Core.
+func (c *Core) With(fields []zapcore.Field) zapcore.Core {
+	return &Core{logger: c.logger, properties: c.addFields(fields)}
+}
+
+// Check adds Core to the checked entry if entry is enabled. It implements zapcore.Core
+// interface in Core.
+func (c *Core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write logs entry with fields. It implements zapcore.Core interface in Core.
+func (c *Core) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	props := c.addFields(fields)
+	if ent.LoggerName != "" {
+		props[NameProperty] = ent.LoggerName
+	}
+	if ent.Stack != "" {
+		props[logger.StackProperty] = ent.Stack
+	}
+	e := c.logger.WithProperties(props)
+	if ent.Caller.Defined {
+		e.WithCaller(caller.ReturnPC(ent.Caller.PC))
+	} else {
+		e.WithCaller(caller.PC(zapPackages...))
+	}
+	e.Log(Level(ent.Level), ent.Message)
+	return nil
+}
+
+// Sync flushes backends of the logger. It implements zapcore.Core interface in Core.
+func (c *Core) Sync() error {
+	return c.logger.Flush()
+}
+
+// addFields returns a copy of Core's properties with fields added.
+func (c *Core) addFields(fields []zapcore.Field) logger.Properties {
+	enc := zapcore.NewMapObjectEncoder()
+	for k, v := range c.properties {
+		enc.Fields[k] = v
+	}
+	for _, f := range fields {
+		if err, ok := f.Interface.(error); ok && f.Type == zapcore.ErrorType {
+			enc.Fields[f.Key] = logger.NewErrorValue(err)
+			continue
+		}
+		f.AddTo(enc)
+	}
+	return logger.Properties(enc.Fields)
+}
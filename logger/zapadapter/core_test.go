@@ -0,0 +1,140 @@
+/*
+ *  Copyright (c) 2018 Samsung Electronics Co., Ltd All Rights Reserved
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License
+ */
+
+package zapadapter
+
+import (
+	"errors"
+	"reflect"
+	"runtime"
+
+	"github.com/SamsungSLAV/slav/logger"
+	"github.com/SamsungSLAV/slav/logger/internal/loggertest"
+	. "github.com/onsi/ginkgo"
+	T "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const thisFile = "core_test.go"
+
+// sink is modified by callSite, so its lines are not optimized away.
+var sink int
+
+// callSite is a function used as a reported caller.
+func callSite() {
+	sink++
+	sink *= 3
+}
+
+var _ = Describe("Core", func() {
+	var (
+		L *logger.Logger
+		c *loggertest.Collector
+		z *zap.Logger
+	)
+
+	BeforeEach(func() {
+		L = logger.NewLogger()
+		Expect(L.SetThreshold(logger.InfoLevel)).To(Succeed())
+		c = new(loggertest.Collector)
+		L.AddBackend("collector", logger.Backend{
+			Filter:     c,
+			Serializer: logger.NewSerializerJSON(),
+			Writer:     logger.NewWriterStderr(),
+		})
+		z = zap.New(NewCore(L))
+	})
+
+	It("should log entries with fields as properties", func() {
+		testError := errors.New("test error")
+		z.With(zap.String("name", "alice")).Named("boruta").
+			Warn("message", zap.Int("count", 3), zap.Error(testError))
+		e := c.Last()
+		Expect(e.Level).To(Equal(logger.WarningLevel))
+		Expect(e.Message).To(Equal("message"))
+		Expect(e.Properties).To(Equal(logger.Properties{
+			"name":               "alice",
+			"count":              int64(3),
+			NameProperty:         "boruta",
+			logger.ErrorProperty: logger.NewErrorValue(testError),
+		}))
+	})
+	It("should not share fields between derived loggers", func() {
+		base := z.With(zap.String("a", "1"))
+		base.With(zap.String("b", "2")).Info("first")
+		base.Info("second")
+		Expect(c.Last().Properties).To(Equal(logger.Properties{"a": "1"}))
+	})
+	It("should skip entries not passing threshold", func() {
+		z.Debug("message")
+		Expect(c.Count()).To(BeZero())
+		Expect(NewCore(L).Enabled(zapcore.DebugLevel)).To(BeFalse())
+		Expect(NewCore(L).Enabled(zapcore.InfoLevel)).To(BeTrue())
+	})
+	It("should use caller reported by zap", func() {
+		z = z.WithOptions(zap.AddCaller())
+		_, _, line, _ := runtime.Caller(0)
+		z.Info("message")
+		e := c.Last()
+		Expect(e.CallContext.File).To(Equal(thisFile))
+		Expect(e.CallContext.Line).To(Equal(line + 1))
+	})
+	It("should treat caller reported by zap as call instruction address", func() {
+		// Find the first instruction of a line: the preceding byte belongs to another line.
+		fn := runtime.FuncForPC(reflect.ValueOf(callSite).Pointer())
+		_, first := fn.FileLine(fn.Entry())
+		pc := fn.Entry()
+		for _, line := fn.FileLine(pc); line == first; _, line = fn.FileLine(pc) {
+			pc++
+		}
+		_, line := fn.FileLine(pc)
+		ent := zapcore.Entry{Level: zapcore.InfoLevel, Message: "message",
+			Caller: zapcore.NewEntryCaller(pc, "", 0, true)}
+		Expect(NewCore(L).Write(ent, nil)).To(Succeed())
+		Expect(c.Last().CallContext.Function).To(Equal("callSite"))
+		Expect(c.Last().CallContext.Line).To(Equal(line))
+	})
+	It("should find caller outside of zap", func() {
+		_, _, line, _ := runtime.Caller(0)
+		z.Sugar().Infow("message", "a", 1)
+		e := c.Last()
+		Expect(e.CallContext.File).To(Equal(thisFile))
+		Expect(e.CallContext.Line).To(Equal(line + 1))
+		Expect(e.Properties).To(Equal(logger.Properties{"a": int64(1)}))
+	})
+	It("should attach stack trace", func() {
+		z = z.WithOptions(zap.AddStacktrace(zapcore.WarnLevel))
+		z.Error("message")
+		Expect(c.Last().Properties).To(HaveKey(logger.StackProperty))
+	})
+	It("should flush logger on sync", func() {
+		Expect(z.Sync()).To(Succeed())
+	})
+	T.DescribeTable("should map levels",
+		func(level zapcore.Level, expected logger.Level) {
+			Expect(Level(level)).To(Equal(expected))
+		},
+		T.Entry("fatal", zapcore.FatalLevel, logger.EmergLevel),
+		T.Entry("panic", zapcore.PanicLevel, logger.AlertLevel),
+		T.Entry("dpanic", zapcore.DPanicLevel, logger.CritLevel),
+		T.Entry("error", zapcore.ErrorLevel, logger.ErrLevel),
+		T.Entry("warning", zapcore.WarnLevel, logger.WarningLevel),
+		T.Entry("info", zapcore.InfoLevel, logger.InfoLevel),
+		T.Entry("debug", zapcore.DebugLevel, logger.DebugLevel),
+	)
+})